@@ -0,0 +1,259 @@
+package accrual
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+)
+
+const (
+	firstYearMonthlyDays = 11 // 1년 미만 근로자 월 1일, 최대 11일
+	baseAnnualDays       = 15 // 1년 이상 근로자 기본 연차
+	maxAnnualDays        = 25 // 가산 포함 연차 상한
+)
+
+// 지급분을 만든 규칙. GivenVacation.GenerateRule 에 저장된다.
+const (
+	RuleMonthly            = "monthly"              // 1년 미만 월 1일
+	RuleHirePreGiven       = "hire_pre_given"       // 입사일 선지급
+	RuleAnniversary        = "anniversary"          // 입사일 기준 매 주년
+	RuleAccountingPreGiven = "accounting_pre_given" // 입사 다음 회계일 선지급
+	RuleAccountingProRata  = "accounting_pro_rata"  // 입사 다음 회계일 비례지급
+	RuleAccountingAnnual   = "accounting_annual"    // 이후 회계일 기준 매년
+	RuleCarryOver          = "carry_over"           // 소멸 처리 때 남은 일수를 이월한 지급분
+)
+
+// Grant 는 발생 규칙에 따라 계산된 한 건의 휴가 지급분이다.
+type Grant struct {
+	GenerateDate time.Time
+	ExpireDate   time.Time
+	Days         float32
+	Rule         string
+	Note         string // 지급 일수 산정 근거
+}
+
+// Policy 는 회사의 휴가 발생 설정이다.
+type Policy struct {
+	GenerateTypeID uint
+	AccountingDay  time.Time // 월/일만 사용한다. zero 값이면 1월 1일
+}
+
+// AnnualDays 는 근속 연수(1년 이상)에 따른 연차 일수를 반환한다.
+// 15일에 최초 1년을 초과하는 근속 2년마다 1일을 가산하고 25일을 넘지 않는다.
+func AnnualDays(serviceYears int) float32 {
+	if serviceYears < 1 {
+		return 0
+	}
+	days := baseAnnualDays + (serviceYears-1)/2
+	if days > maxAnnualDays {
+		days = maxAnnualDays
+	}
+	return float32(days)
+}
+
+// Calculate 는 입사일부터 asOf 까지 발생했어야 하는 모든 지급분을 발생일 순으로 반환한다.
+func Calculate(hireDate time.Time, policy Policy, asOf time.Time) ([]Grant, error) {
+	hire := calendar.DateOnly(hireDate)
+	until := calendar.DateOnly(asOf)
+	if until.Before(hire) {
+		return nil, nil
+	}
+
+	var grants []Grant
+	switch policy.GenerateTypeID {
+	case enums.VacationGenerateTypeAnnualNormal,
+		enums.VacationGenerateTypeAnnualThisYearPreGiven,
+		enums.VacationGenerateTypeAnnualOneYearPreGiven:
+		grants = append(grants, firstYearGrants(hire, policy.GenerateTypeID, addMonths(hire, 12))...)
+		grants = append(grants, anniversaryGrants(hire, until)...)
+	case enums.VacationGenerateTypePreAccountingNormal,
+		enums.VacationGenerateTypePreAccountingThisYearPreGiven,
+		enums.VacationGenerateTypePreAccountingOneYearPreGiven,
+		enums.VacationGenerateTypeProAccountingNormal,
+		enums.VacationGenerateTypeProAccountingThisYearPreGiven,
+		enums.VacationGenerateTypeProAccountingOneYearPreGiven:
+		grants = append(grants, accountingGrants(hire, policy, until)...)
+	default:
+		return nil, fmt.Errorf("unsupported vacation generate type: %d", policy.GenerateTypeID)
+	}
+
+	return filterUntil(grants, until), nil
+}
+
+// 입사 1년차 지급분. 월 지급일이 yearEnd 이전인 달까지만 지급하고 모두 입사 1주년에 소멸한다.
+// 입사일 기준이면 yearEnd 는 입사 1주년, 회계일 기준이면 입사 후 첫 회계일이다.
+func firstYearGrants(hire time.Time, generateTypeID uint, yearEnd time.Time) []Grant {
+	expire := addMonths(hire, 12)
+
+	months := 0
+	for months < firstYearMonthlyDays && addMonths(hire, months+1).Before(yearEnd) {
+		months++
+	}
+
+	var grants []Grant
+	firstMonth := 1
+	switch generateTypeID {
+	case enums.VacationGenerateTypeAnnualThisYearPreGiven,
+		enums.VacationGenerateTypePreAccountingThisYearPreGiven,
+		enums.VacationGenerateTypeProAccountingThisYearPreGiven:
+		// 입사한 해의 남은 달만큼 입사일에 선지급하고 나머지는 매월 지급
+		preGiven := months
+		if generateTypeID == enums.VacationGenerateTypeAnnualThisYearPreGiven {
+			preGiven = 12 - int(hire.Month())
+		}
+		if preGiven > 0 {
+			grants = append(grants, Grant{
+				GenerateDate: hire,
+				ExpireDate:   expire,
+				Days:         float32(preGiven),
+				Rule:         RuleHirePreGiven,
+				Note:         fmt.Sprintf("입사한 해 남은 %d개월분 선지급", preGiven),
+			})
+		}
+		firstMonth = preGiven + 1
+	case enums.VacationGenerateTypeAnnualOneYearPreGiven,
+		enums.VacationGenerateTypePreAccountingOneYearPreGiven,
+		enums.VacationGenerateTypeProAccountingOneYearPreGiven:
+		return []Grant{{
+			GenerateDate: hire,
+			ExpireDate:   expire,
+			Days:         firstYearMonthlyDays,
+			Rule:         RuleHirePreGiven,
+			Note:         fmt.Sprintf("1년 미만 월차 %d일 선지급", firstYearMonthlyDays),
+		}}
+	}
+
+	for month := firstMonth; month <= months; month++ {
+		grants = append(grants, Grant{
+			GenerateDate: addMonths(hire, month),
+			ExpireDate:   expire,
+			Days:         1,
+			Rule:         RuleMonthly,
+			Note:         fmt.Sprintf("입사 %d개월 개근", month),
+		})
+	}
+	return grants
+}
+
+// 입사일 기준 매 주년 지급분. 다음 주년에 소멸한다.
+func anniversaryGrants(hire, until time.Time) []Grant {
+	var grants []Grant
+	for years := 1; ; years++ {
+		generate := addMonths(hire, 12*years)
+		if generate.After(until) {
+			break
+		}
+		grants = append(grants, Grant{
+			GenerateDate: generate,
+			ExpireDate:   addMonths(hire, 12*(years+1)),
+			Days:         AnnualDays(years),
+			Rule:         RuleAnniversary,
+			Note:         fmt.Sprintf("근속 %d년", years),
+		})
+	}
+	return grants
+}
+
+// 회계일 기준 지급분. 입사 후 첫 회계일까지는 월 지급, 첫 회계일에는 선지급 또는 근무일수 비례지급,
+// 이후 회계일마다 회계연도 근속 연수에 따라 지급한다. 회계일 지급분은 다음 회계일에 소멸한다.
+func accountingGrants(hire time.Time, policy Policy, until time.Time) []Grant {
+	first := NextAccountingDate(hire, policy.AccountingDay)
+	grants := firstYearGrants(hire, policy.GenerateTypeID, first)
+
+	next := addMonths(first, 12)
+	if isProRata(policy.GenerateTypeID) {
+		fiscalDays := daysBetween(addMonths(first, -12), first)
+		workedDays := daysBetween(hire, first)
+		grants = append(grants, Grant{
+			GenerateDate: first,
+			ExpireDate:   next,
+			Days:         roundUpHalf(baseAnnualDays * float64(workedDays) / float64(fiscalDays)),
+			Rule:         RuleAccountingProRata,
+			Note:         fmt.Sprintf("%d일 × %d/%d일 비례지급", baseAnnualDays, workedDays, fiscalDays),
+		})
+	} else {
+		grants = append(grants, Grant{
+			GenerateDate: first,
+			ExpireDate:   next,
+			Days:         baseAnnualDays,
+			Rule:         RuleAccountingPreGiven,
+			Note:         fmt.Sprintf("입사 다음 회계일 %d일 선지급", baseAnnualDays),
+		})
+	}
+
+	for years := 1; ; years++ {
+		generate := addMonths(first, 12*years)
+		if generate.After(until) {
+			break
+		}
+		grants = append(grants, Grant{
+			GenerateDate: generate,
+			ExpireDate:   addMonths(first, 12*(years+1)),
+			Days:         AnnualDays(years),
+			Rule:         RuleAccountingAnnual,
+			Note:         fmt.Sprintf("회계연도 근속 %d년", years),
+		})
+	}
+	return grants
+}
+
+// NextAccountingDate 는 date 이후(당일 제외) 처음 돌아오는 회계일을 반환한다.
+func NextAccountingDate(date, accountingDay time.Time) time.Time {
+	month, day := time.January, 1
+	if !accountingDay.IsZero() {
+		month, day = accountingDay.Month(), accountingDay.Day()
+	}
+	date = calendar.DateOnly(date)
+	next := monthDay(date.Year(), month, day, date.Location())
+	if !next.After(date) {
+		next = monthDay(date.Year()+1, month, day, date.Location())
+	}
+	return next
+}
+
+func isProRata(generateTypeID uint) bool {
+	switch generateTypeID {
+	case enums.VacationGenerateTypeProAccountingNormal,
+		enums.VacationGenerateTypeProAccountingThisYearPreGiven,
+		enums.VacationGenerateTypeProAccountingOneYearPreGiven:
+		return true
+	}
+	return false
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(calendar.DateOnly(to).Sub(calendar.DateOnly(from)).Hours() / 24))
+}
+
+// 반차 단위(0.5일)로 올림
+func roundUpHalf(days float64) float32 {
+	return float32(math.Ceil(days*2) / 2)
+}
+
+func filterUntil(grants []Grant, until time.Time) []Grant {
+	filtered := make([]Grant, 0, len(grants))
+	for _, grant := range grants {
+		if !grant.GenerateDate.After(until) {
+			filtered = append(filtered, grant)
+		}
+	}
+	return filtered
+}
+
+// addMonths 는 월말을 넘기지 않도록 일자를 보정해 n개월을 더한다. (1/31 + 1개월 = 2/28)
+func addMonths(t time.Time, months int) time.Time {
+	return monthDay(t.Year(), t.Month()+time.Month(months), t.Day(), t.Location())
+}
+
+// monthDay 는 day 가 해당 월의 말일을 넘으면 말일로 보정한 날짜를 반환한다.
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,170 @@
+package accrual
+
+import (
+	"testing"
+	"time"
+
+	"cywell.com/vacation-promotion/app/enums"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAnnualDays(t *testing.T) {
+	tests := []struct {
+		years int
+		want  float32
+	}{
+		{0, 0},
+		{1, 15},
+		{2, 15},
+		{3, 16},
+		{4, 16},
+		{5, 17},
+		{20, 24},
+		{21, 25},
+		{30, 25},
+	}
+	for _, tt := range tests {
+		if got := AnnualDays(tt.years); got != tt.want {
+			t.Errorf("AnnualDays(%d) = %v, want %v", tt.years, got, tt.want)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name   string
+		hire   time.Time
+		policy Policy
+		asOf   time.Time
+		rule   string
+		dates  []time.Time
+		days   []float32
+	}{
+		{
+			name:   "1년 미만 월 1일 최대 11일",
+			hire:   date(2024, 1, 15),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal},
+			asOf:   date(2025, 1, 15),
+			rule:   RuleMonthly,
+			dates: []time.Time{
+				date(2024, 2, 15), date(2024, 3, 15), date(2024, 4, 15), date(2024, 5, 15),
+				date(2024, 6, 15), date(2024, 7, 15), date(2024, 8, 15), date(2024, 9, 15),
+				date(2024, 10, 15), date(2024, 11, 15), date(2024, 12, 15),
+			},
+			days: []float32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		},
+		{
+			name:   "asOf 이후 지급분은 제외",
+			hire:   date(2024, 1, 15),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal},
+			asOf:   date(2024, 3, 14),
+			rule:   RuleMonthly,
+			dates:  []time.Time{date(2024, 2, 15)},
+			days:   []float32{1},
+		},
+		{
+			name:   "입사일 기준 근속 3년부터 가산",
+			hire:   date(2020, 3, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal},
+			asOf:   date(2023, 3, 1),
+			rule:   RuleAnniversary,
+			dates:  []time.Time{date(2021, 3, 1), date(2022, 3, 1), date(2023, 3, 1)},
+			days:   []float32{15, 15, 16},
+		},
+		{
+			name:   "가산 연차는 25일을 넘지 않음",
+			hire:   date(1990, 3, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal},
+			asOf:   date(2012, 3, 1),
+			rule:   RuleAnniversary,
+			days:   []float32{15, 15, 16, 16, 17, 17, 18, 18, 19, 19, 20, 20, 21, 21, 22, 22, 23, 23, 24, 24, 25, 25},
+		},
+		{
+			name:   "회계연도 비례지급",
+			hire:   date(2024, 7, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeProAccountingNormal},
+			asOf:   date(2025, 1, 1),
+			rule:   RuleAccountingProRata,
+			dates:  []time.Time{date(2025, 1, 1)},
+			days:   []float32{8}, // 15일 × 184/366 = 7.54 → 반차 단위 올림
+		},
+		{
+			name:   "회계연도 비례지급 전 월 지급",
+			hire:   date(2024, 7, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeProAccountingNormal},
+			asOf:   date(2025, 1, 1),
+			rule:   RuleMonthly,
+			dates:  []time.Time{date(2024, 8, 1), date(2024, 9, 1), date(2024, 10, 1), date(2024, 11, 1), date(2024, 12, 1)},
+			days:   []float32{1, 1, 1, 1, 1},
+		},
+		{
+			name:   "회계일이 있는 비례지급",
+			hire:   date(2024, 1, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeProAccountingNormal, AccountingDay: date(2000, 7, 1)},
+			asOf:   date(2025, 7, 1),
+			rule:   RuleAccountingProRata,
+			dates:  []time.Time{date(2024, 7, 1)},
+			days:   []float32{7.5}, // 15일 × 182/366 = 7.46 → 반차 단위 올림
+		},
+		{
+			name:   "회계연도 비례지급 이후 매년",
+			hire:   date(2024, 1, 1),
+			policy: Policy{GenerateTypeID: enums.VacationGenerateTypeProAccountingNormal, AccountingDay: date(2000, 7, 1)},
+			asOf:   date(2025, 7, 1),
+			rule:   RuleAccountingAnnual,
+			dates:  []time.Time{date(2025, 7, 1)},
+			days:   []float32{15},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants, err := Calculate(tt.hire, tt.policy, tt.asOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dates []time.Time
+			var days []float32
+			for _, grant := range grants {
+				if grant.Rule == tt.rule {
+					dates = append(dates, grant.GenerateDate)
+					days = append(days, grant.Days)
+				}
+			}
+			if len(days) != len(tt.days) {
+				t.Fatalf("%s grants = %v, want %v", tt.rule, days, tt.days)
+			}
+			for i := range tt.days {
+				if days[i] != tt.days[i] {
+					t.Errorf("grant %d days = %v, want %v", i, days[i], tt.days[i])
+				}
+				if tt.dates != nil && !dates[i].Equal(tt.dates[i]) {
+					t.Errorf("grant %d date = %s, want %s", i, dates[i].Format("2006-01-02"), tt.dates[i].Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateFirstYearExpiresAtAnniversary(t *testing.T) {
+	grants, err := Calculate(date(2024, 1, 31), Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal}, date(2025, 1, 31))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, grant := range grants {
+		if grant.Rule == RuleMonthly && !grant.ExpireDate.Equal(date(2025, 1, 31)) {
+			t.Errorf("monthly grant %s expires %s, want 2025-01-31", grant.GenerateDate.Format("2006-01-02"), grant.ExpireDate.Format("2006-01-02"))
+		}
+	}
+	if got := grants[0].GenerateDate; !got.Equal(date(2024, 2, 29)) {
+		t.Errorf("first monthly grant = %s, want 2024-02-29", got.Format("2006-01-02"))
+	}
+}
+
+func TestCalculateUnsupportedType(t *testing.T) {
+	if _, err := Calculate(date(2024, 1, 1), Policy{GenerateTypeID: 99}, date(2025, 1, 1)); err == nil {
+		t.Error("expected error for unsupported generate type")
+	}
+}
@@ -0,0 +1,156 @@
+package accrual
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"cywell.com/vacation-promotion/database"
+	"gorm.io/gorm"
+)
+
+// Engine 은 회사 휴가 발생 설정에 따라 GivenVacation 을 생성한다.
+// 이미 생성된 지급분은 건너뛰므로 여러 번 실행해도 안전하다.
+type Engine struct {
+	db  *database.Database
+	now func() time.Time
+}
+
+// NewEngine 은 now 를 기준 시각으로 사용하는 Engine 을 만든다. now 가 nil 이면 time.Now 를 사용한다.
+func NewEngine(db *database.Database, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	return &Engine{db: db, now: now}
+}
+
+// AccrueMember 는 한 멤버의 누락된 지급분을 생성하고 새로 생성된 GivenVacation 을 반환한다.
+func (e *Engine) AccrueMember(memberID uint) ([]models.GivenVacation, error) {
+	var member models.Member
+	if err := e.db.Preload("Company").First(&member, memberID).Error; err != nil {
+		return nil, err
+	}
+
+	var created []models.GivenVacation
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = e.accrue(tx, member.Company, member)
+		return err
+	})
+	return created, err
+}
+
+// AccrueCompany 는 회사의 모든 재직 멤버에 대해 AccrueMember 를 수행한다.
+// 실패한 멤버가 있어도 나머지 멤버는 계속 처리하고 멤버별 오류를 모아 반환한다.
+func (e *Engine) AccrueCompany(companyID uint) ([]models.GivenVacation, error) {
+	var company models.Company
+	if err := e.db.First(&company, companyID).Error; err != nil {
+		return nil, err
+	}
+
+	var members []models.Member
+	if err := e.db.Where("company_id = ? AND is_active = ?", companyID, true).Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	created := make([]models.GivenVacation, 0)
+	var errs []error
+	for _, member := range members {
+		err := e.db.Transaction(func(tx *gorm.DB) error {
+			memberCreated, err := e.accrue(tx, company, member)
+			if err != nil {
+				return err
+			}
+			created = append(created, memberCreated...)
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("member %d: %w", member.ID, err))
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+func (e *Engine) accrue(tx *gorm.DB, company models.Company, member models.Member) ([]models.GivenVacation, error) {
+	asOf := e.now()
+	if member.RetireDate != nil && member.RetireDate.Before(asOf) {
+		asOf = *member.RetireDate
+	}
+
+	grants, err := Calculate(member.HireDate, policyOf(company), asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []models.GivenVacation
+	if err := tx.Where("member_id = ?", member.ID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	given := make(map[string]bool, len(existing))
+	// 규칙을 저장하기 전에 만든 지급분은 발생일과 일수로 같은 지급분인지 본다
+	legacy := make(map[string][]uint)
+	for _, gv := range existing {
+		if gv.GenerateRule == "" {
+			key := legacyKey(gv.GenerateDate, gv.GivenDays)
+			legacy[key] = append(legacy[key], gv.ID)
+			continue
+		}
+		given[grantKey(gv.GenerateRule, gv.GenerateDate)] = true
+	}
+
+	created := make([]models.GivenVacation, 0)
+	for _, grant := range grants {
+		if given[grantKey(grant.Rule, grant.GenerateDate)] {
+			continue
+		}
+		if ids := legacy[legacyKey(grant.GenerateDate, grant.Days)]; len(ids) > 0 {
+			// 이미 지급한 것으로 보고 규칙을 채워 둔다
+			if err := tx.Model(&models.GivenVacation{}).Where("id = ?", ids[0]).Update("generate_rule", grant.Rule).Error; err != nil {
+				return nil, err
+			}
+			legacy[legacyKey(grant.GenerateDate, grant.Days)] = ids[1:]
+			continue
+		}
+		givenVacation := models.GivenVacation{
+			MemberID:                 member.ID,
+			VacationGenerateTypeID:   company.VacationGenerateTypeID,
+			VacationPromotionStateID: enums.VacationPromotionStateNone,
+			Year:                     grant.GenerateDate.Year(),
+			GivenDays:                grant.Days,
+			GenerateDate:             grant.GenerateDate,
+			ExpireDate:               grant.ExpireDate,
+			GenerateRule:             grant.Rule,
+			GenerateNote:             grant.Note,
+		}
+		if err := tx.Create(&givenVacation).Error; err != nil {
+			return nil, err
+		}
+		if err := ledger.Grant(tx, &givenVacation, grant.Note); err != nil {
+			return nil, err
+		}
+		if err := webhook.Emit(tx, company.ID, webhook.EventGrantCreated, webhook.GrantDataOf(givenVacation)); err != nil {
+			return nil, err
+		}
+		created = append(created, givenVacation)
+	}
+	return created, nil
+}
+
+func policyOf(company models.Company) Policy {
+	return Policy{
+		GenerateTypeID: company.VacationGenerateTypeID,
+		AccountingDay:  company.AccountingDay,
+	}
+}
+
+func grantKey(rule string, generateDate time.Time) string {
+	return fmt.Sprintf("%s:%s", rule, generateDate.Format("2006-01-02"))
+}
+
+func legacyKey(generateDate time.Time, days float32) string {
+	return fmt.Sprintf("%s:%.2f", generateDate.Format("2006-01-02"), days)
+}
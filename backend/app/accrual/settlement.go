@@ -0,0 +1,131 @@
+package accrual
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"gorm.io/gorm"
+)
+
+// RuleRetirementSettlement 는 퇴직 정산으로 추가 지급된 지급분의 규칙이다.
+const RuleRetirementSettlement = "retirement_settlement"
+
+var ErrNotRetired = errors.New("퇴직일이 설정되지 않은 멤버입니다")
+
+// Settlement 는 퇴직 시 회계일 기준 지급 총량과 입사일 기준 지급 총량의 비교 결과이다.
+type Settlement struct {
+	MemberID     uint
+	GrantedDays  float32 // 실제 지급된 일수. 이월분과 퇴직 정산분은 원래 지급분과 겹치므로 제외한다
+	SettledDays  float32 // 이미 퇴직 정산으로 보전 지급한 일수
+	HireDateDays float32 // 입사일 기준이었다면 지급되었을 일수
+	DiffDays     float32 // HireDateDays - GrantedDays - SettledDays. 0 보다 크면 부족분으로 정산 대상
+	DailyWage    int64
+	Allowance    int64 // 부족분 × 통상임금 일급. 부족분이 없으면 0
+}
+
+// Settle 은 퇴직한 멤버의 지급 이력을 입사일 기준 지급량과 비교한다.
+func (e *Engine) Settle(memberID uint, dailyWage int64) (Settlement, error) {
+	return e.settle(e.db.DB, memberID, dailyWage)
+}
+
+// ApplySettlement 는 Settle 결과 부족분이 있으면 퇴직일자로 보전 지급분을 생성한다.
+// 이미 보전 지급한 일수는 부족분에서 빼므로 다시 호출해도 중복 지급되지 않는다.
+func (e *Engine) ApplySettlement(memberID uint, dailyWage int64) (Settlement, *models.GivenVacation, error) {
+	var settlement Settlement
+	var created *models.GivenVacation
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		settlement, err = e.settle(tx, memberID, dailyWage)
+		if err != nil {
+			return err
+		}
+		if settlement.DiffDays <= 0 {
+			return nil
+		}
+
+		var member models.Member
+		if err := tx.Preload("Company").First(&member, memberID).Error; err != nil {
+			return err
+		}
+		retireDate := calendar.DateOnly(*member.RetireDate)
+		created = &models.GivenVacation{
+			MemberID:                 member.ID,
+			VacationGenerateTypeID:   member.Company.VacationGenerateTypeID,
+			VacationPromotionStateID: enums.VacationPromotionStateNone,
+			Year:                     retireDate.Year(),
+			GivenDays:                settlement.DiffDays,
+			GenerateDate:             retireDate,
+			ExpireDate:               retireDate,
+			GenerateRule:             RuleRetirementSettlement,
+			GenerateNote:             fmt.Sprintf("입사일 기준 %.1f일 - 지급 %.1f일 퇴직 정산", settlement.HireDateDays, settlement.GrantedDays),
+		}
+		if err := tx.Create(created).Error; err != nil {
+			return err
+		}
+		if err := ledger.Grant(tx, created, created.GenerateNote); err != nil {
+			return err
+		}
+		return webhook.Emit(tx, member.CompanyID, webhook.EventGrantCreated, webhook.GrantDataOf(*created))
+	})
+	return settlement, created, err
+}
+
+func (e *Engine) settle(tx *gorm.DB, memberID uint, dailyWage int64) (Settlement, error) {
+	var member models.Member
+	if err := tx.First(&member, memberID).Error; err != nil {
+		return Settlement{}, err
+	}
+	if member.RetireDate == nil {
+		return Settlement{}, ErrNotRetired
+	}
+
+	var grants []models.GivenVacation
+	if err := tx.Where("member_id = ?", memberID).Find(&grants).Error; err != nil {
+		return Settlement{}, err
+	}
+	granted, settled := grantedDays(grants)
+
+	// 법정 기준인 입사일 기준 월 1일 지급 방식으로 퇴직일까지의 지급량을 계산
+	hireDateGrants, err := Calculate(member.HireDate, Policy{GenerateTypeID: enums.VacationGenerateTypeAnnualNormal}, *member.RetireDate)
+	if err != nil {
+		return Settlement{}, err
+	}
+	var hireDateDays float32
+	for _, grant := range hireDateGrants {
+		hireDateDays += grant.Days
+	}
+
+	settlement := Settlement{
+		MemberID:     memberID,
+		GrantedDays:  granted,
+		SettledDays:  settled,
+		HireDateDays: hireDateDays,
+		DiffDays:     hireDateDays - granted - settled,
+		DailyWage:    dailyWage,
+	}
+	if settlement.DiffDays > 0 {
+		settlement.Allowance = int64(math.Round(float64(settlement.DiffDays) * float64(dailyWage)))
+	}
+	return settlement, nil
+}
+
+// grantedDays 는 원래 지급분의 합계와 퇴직 정산분의 합계를 구한다.
+// 이월분은 이전 지급분의 남은 일수를 옮긴 것이므로 어느 쪽에도 넣지 않는다.
+func grantedDays(grants []models.GivenVacation) (granted, settled float32) {
+	for _, grant := range grants {
+		switch grant.GenerateRule {
+		case RuleCarryOver:
+		case RuleRetirementSettlement:
+			settled += grant.GivenDays
+		default:
+			granted += grant.GivenDays
+		}
+	}
+	return granted, settled
+}
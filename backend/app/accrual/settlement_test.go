@@ -0,0 +1,60 @@
+package accrual
+
+import (
+	"testing"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+func TestGrantedDays(t *testing.T) {
+	tests := []struct {
+		name    string
+		grants  []models.GivenVacation
+		granted float32
+		settled float32
+	}{
+		{
+			name: "원래 지급분만",
+			grants: []models.GivenVacation{
+				{GenerateRule: RuleAccountingPreGiven, GivenDays: 15},
+				{GenerateRule: RuleAccountingAnnual, GivenDays: 15},
+			},
+			granted: 30,
+		},
+		{
+			name: "이월분은 제외",
+			grants: []models.GivenVacation{
+				{GenerateRule: RuleAccountingPreGiven, GivenDays: 15},
+				{GenerateRule: RuleCarryOver, GivenDays: 5},
+				{GenerateRule: RuleAccountingAnnual, GivenDays: 15},
+			},
+			granted: 30,
+		},
+		{
+			name: "퇴직 정산분은 따로 합산",
+			grants: []models.GivenVacation{
+				{GenerateRule: RuleMonthly, GivenDays: 1},
+				{GenerateRule: RuleAccountingProRata, GivenDays: 8},
+				{GenerateRule: RuleCarryOver, GivenDays: 3},
+				{GenerateRule: RuleRetirementSettlement, GivenDays: 4},
+			},
+			granted: 9,
+			settled: 4,
+		},
+		{
+			name: "규칙이 없는 이전 지급분은 원래 지급분",
+			grants: []models.GivenVacation{
+				{GivenDays: 15},
+			},
+			granted: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			granted, settled := grantedDays(tt.grants)
+			if granted != tt.granted || settled != tt.settled {
+				t.Errorf("grantedDays() = %v, %v, want %v, %v", granted, settled, tt.granted, tt.settled)
+			}
+		})
+	}
+}
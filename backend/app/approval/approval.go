@@ -0,0 +1,129 @@
+package approval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+)
+
+// ApprovalReminder.Kind 값
+const (
+	KindDigest     = "digest"     // 결재자별 대기 요약. 하루에 한 번
+	KindReminder   = "reminder"   // 결재자 독촉. 결재 단계마다 한 번
+	KindEscalation = "escalation" // 회사 관리자 보고. 결재 단계마다 한 번
+)
+
+// Engine 은 결재 차례에 머물러 있는 휴가 계획을 결재자와 회사 관리자에게 알린다.
+// 보낸 알림은 ApprovalReminder 로 남기므로 여러 번 실행해도 같은 알림을 다시 보내지 않는다.
+type Engine struct {
+	db  *database.Database
+	now func() time.Time
+}
+
+// NewEngine 은 now 를 기준 시각으로 사용하는 Engine 을 만든다. now 가 nil 이면 time.Now 를 사용한다.
+func NewEngine(db *database.Database, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	return &Engine{db: db, now: now}
+}
+
+// Pending 은 결재자 차례에서 기다리는 휴가 계획 하나이다.
+type Pending struct {
+	Order       models.ApproverOrder // Member, VacationPlan(Member, ApplyVacations, ApproverOrders) 포함
+	Since       time.Time            // 결재 차례가 된 날. 첫 결재자는 신청일, 그 뒤는 앞 결재자의 승인일
+	WaitingDays int
+}
+
+// Pending 은 회사에서 결재를 기다리는 휴가 계획을 결재자, 신청일 순으로 반환한다.
+// 거절되거나 최종 승인된 계획은 제외한다.
+func (e *Engine) Pending(companyID uint) ([]Pending, error) {
+	var orders []models.ApproverOrder
+	if err := e.db.
+		Joins("JOIN vacation_plans ON vacation_plans.id = approver_orders.vacation_plan_id").
+		Joins("JOIN members ON members.id = vacation_plans.member_id").
+		Where("members.company_id = ?", companyID).
+		Where("vacation_plans.reject_state = ? AND vacation_plans.complete_state = ?", false, false).
+		Where("vacation_plans.approve_stage = approver_orders.`order` - 1").
+		Preload("Member").
+		Preload("VacationPlan.Member").
+		Preload("VacationPlan.ApplyVacations").
+		Preload("VacationPlan.ApproverOrders").
+		Order("approver_orders.member_id ASC, vacation_plans.apply_date ASC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	today := calendar.DateOnly(e.now())
+	pending := make([]Pending, 0, len(orders))
+	for _, order := range orders {
+		since := calendar.DateOnly(stageStart(order))
+		waiting := int(today.Sub(since).Hours() / 24)
+		if waiting < 0 {
+			waiting = 0
+		}
+		pending = append(pending, Pending{Order: order, Since: since, WaitingDays: waiting})
+	}
+	return pending, nil
+}
+
+// stageStart 는 결재 차례가 된 시각이다. 앞 결재자의 승인일이 없으면 신청일로 본다.
+func stageStart(order models.ApproverOrder) time.Time {
+	for _, previous := range order.VacationPlan.ApproverOrders {
+		if previous.Order == order.Order-1 && previous.DecisionDate != nil {
+			return *previous.DecisionDate
+		}
+	}
+	return order.VacationPlan.ApplyDate
+}
+
+// sent 는 결재 단계별로 이미 보낸 알림 종류이다. 요약은 오늘 보낸 것만 담는다.
+func (e *Engine) sent(pending []Pending, today time.Time) (map[uint]map[string]bool, error) {
+	result := make(map[uint]map[string]bool, len(pending))
+	if len(pending) == 0 {
+		return result, nil
+	}
+	orderIDs := make([]uint, 0, len(pending))
+	for _, p := range pending {
+		orderIDs = append(orderIDs, p.Order.ID)
+	}
+
+	var reminders []models.ApprovalReminder
+	if err := e.db.Where("approver_order_id IN ?", orderIDs).Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+	for _, reminder := range reminders {
+		if reminder.Kind == KindDigest && !calendar.DateOnly(reminder.SentOn).Equal(today) {
+			continue
+		}
+		if result[reminder.ApproverOrderID] == nil {
+			result[reminder.ApproverOrderID] = make(map[string]bool)
+		}
+		result[reminder.ApproverOrderID][reminder.Kind] = true
+	}
+	return result, nil
+}
+
+// planSummary 는 "홍길동님의 휴가 신청(10-20 ~ 10-21, 10-27)" 형태의 계획 설명이다.
+func planSummary(plan models.VacationPlan) string {
+	vacations := append([]models.ApplyVacation(nil), plan.ApplyVacations...)
+	sort.Slice(vacations, func(i, j int) bool { return vacations[i].StartDate.Before(vacations[j].StartDate) })
+
+	periods := make([]string, 0, len(vacations))
+	for _, vacation := range vacations {
+		period := vacation.StartDate.Format("2006-01-02")
+		if !calendar.DateOnly(vacation.EndDate).Equal(calendar.DateOnly(vacation.StartDate)) {
+			period += " ~ " + vacation.EndDate.Format("2006-01-02")
+		}
+		periods = append(periods, period)
+	}
+	if len(periods) == 0 {
+		return fmt.Sprintf("%s님의 휴가 신청(휴가 계획 %d)", plan.Member.Name, plan.ID)
+	}
+	return fmt.Sprintf("%s님의 휴가 신청(%s)", plan.Member.Name, strings.Join(periods, ", "))
+}
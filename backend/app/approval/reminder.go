@@ -0,0 +1,167 @@
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"gorm.io/gorm"
+)
+
+// NotifyApprover 는 결재 차례가 된 결재자에게 휴가 신청 알림을 보낸다.
+func NotifyApprover(tx *gorm.DB, planID, approverID uint, applicant string) error {
+	_, err := notify.Send(tx, notify.Message{
+		TypeID:         enums.NotificationTypeVacationApplied,
+		Contents:       fmt.Sprintf("%s님의 휴가 신청(휴가 계획 %d) 결재 차례입니다.", applicant, planID),
+		VacationPlanID: &planID,
+	}, []uint{approverID})
+	return err
+}
+
+// SendDigests 는 결재자마다 결재를 기다리는 휴가 계획 목록을 알림 하나로 보내고 보낸 알림 수를 반환한다.
+// 오늘 요약에 이미 넣은 계획은 다시 넣지 않는다. 요약을 끈 회사는 보내지 않는다.
+func (e *Engine) SendDigests(companyID uint) (int, error) {
+	setting, err := LoadSetting(e.db.DB, companyID)
+	if err != nil || !setting.DigestEnabled {
+		return 0, err
+	}
+	pending, err := e.Pending(companyID)
+	if err != nil {
+		return 0, err
+	}
+	today := calendar.DateOnly(e.now())
+	sent, err := e.sent(pending, today)
+	if err != nil {
+		return 0, err
+	}
+
+	byApprover := make(map[uint][]Pending)
+	approverIDs := make([]uint, 0)
+	for _, p := range pending {
+		if sent[p.Order.ID][KindDigest] {
+			continue
+		}
+		if _, ok := byApprover[p.Order.MemberID]; !ok {
+			approverIDs = append(approverIDs, p.Order.MemberID)
+		}
+		byApprover[p.Order.MemberID] = append(byApprover[p.Order.MemberID], p)
+	}
+
+	count := 0
+	for _, approverID := range approverIDs {
+		items := byApprover[approverID]
+		lines := make([]string, 0, len(items)+1)
+		lines = append(lines, fmt.Sprintf("결재를 기다리는 휴가 신청이 %d건 있습니다.", len(items)))
+		for _, p := range items {
+			lines = append(lines, fmt.Sprintf("- %s: %d일째 대기", planSummary(p.Order.VacationPlan), p.WaitingDays))
+		}
+
+		err := e.db.Transaction(func(tx *gorm.DB) error {
+			notification, err := notify.Send(tx, notify.Message{
+				TypeID:   enums.NotificationTypeApprovalDigest,
+				Contents: strings.Join(lines, "\n"),
+			}, []uint{approverID})
+			if err != nil {
+				return err
+			}
+			for _, p := range items {
+				if _, err := newReminder(tx, p, KindDigest, notification.ID, today); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Remind 는 결재 차례가 된 지 ReminderDays 가 지난 계획을 결재자에게 다시 알리고,
+// EscalationDays 가 지나면 회사 관리자(MemberAdmin)에게 알린다. 결재 단계마다 종류별로 한 번만 보낸다.
+// 회사 관리자가 없으면 보고를 남기지 않고 다음 실행에서 다시 확인한다.
+func (e *Engine) Remind(companyID uint) ([]models.ApprovalReminder, error) {
+	reminders := make([]models.ApprovalReminder, 0)
+	setting, err := LoadSetting(e.db.DB, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if setting.ReminderDays == 0 && setting.EscalationDays == 0 {
+		return reminders, nil
+	}
+	pending, err := e.Pending(companyID)
+	if err != nil {
+		return nil, err
+	}
+	today := calendar.DateOnly(e.now())
+	sent, err := e.sent(pending, today)
+	if err != nil {
+		return nil, err
+	}
+	adminIDs, err := notify.CompanyAdmins(e.db.DB, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pending {
+		summary := planSummary(p.Order.VacationPlan)
+		planID := p.Order.VacationPlanID
+
+		if setting.ReminderDays > 0 && p.WaitingDays >= setting.ReminderDays && !sent[p.Order.ID][KindReminder] {
+			reminder, err := e.remind(p, KindReminder, today, notify.Message{
+				TypeID:         enums.NotificationTypeApprovalReminder,
+				Contents:       fmt.Sprintf("%s이 %d일째 결재를 기다리고 있습니다.", summary, p.WaitingDays),
+				VacationPlanID: &planID,
+			}, []uint{p.Order.MemberID})
+			if err != nil {
+				return reminders, err
+			}
+			reminders = append(reminders, reminder)
+		}
+
+		if setting.EscalationDays > 0 && p.WaitingDays >= setting.EscalationDays && !sent[p.Order.ID][KindEscalation] && len(adminIDs) > 0 {
+			reminder, err := e.remind(p, KindEscalation, today, notify.Message{
+				TypeID:         enums.NotificationTypeApprovalReminder,
+				Contents:       fmt.Sprintf("%s이 결재자 %s님에게서 %d일째 결재를 기다리고 있습니다.", summary, p.Order.Member.Name, p.WaitingDays),
+				VacationPlanID: &planID,
+			}, adminIDs)
+			if err != nil {
+				return reminders, err
+			}
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+func (e *Engine) remind(p Pending, kind string, today time.Time, message notify.Message, recipientIDs []uint) (models.ApprovalReminder, error) {
+	var reminder models.ApprovalReminder
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		notification, err := notify.Send(tx, message, recipientIDs)
+		if err != nil {
+			return err
+		}
+		reminder, err = newReminder(tx, p, kind, notification.ID, today)
+		return err
+	})
+	return reminder, err
+}
+
+func newReminder(tx *gorm.DB, p Pending, kind string, notificationID uint, today time.Time) (models.ApprovalReminder, error) {
+	reminder := models.ApprovalReminder{
+		ApproverOrderID: p.Order.ID,
+		VacationPlanID:  p.Order.VacationPlanID,
+		Kind:            kind,
+		SentOn:          today,
+		NotificationID:  notificationID,
+		WaitingDays:     p.WaitingDays,
+	}
+	err := tx.Create(&reminder).Error
+	return reminder, err
+}
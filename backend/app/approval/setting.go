@@ -0,0 +1,42 @@
+package approval
+
+import (
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// 기본 독촉 일수. 결재 차례가 된 지 3일이면 결재자에게, 5일이면 회사 관리자에게 알린다
+const (
+	DefaultReminderDays   = 3
+	DefaultEscalationDays = 5
+)
+
+// DefaultSetting 은 설정을 저장하지 않은 회사의 결재 알림 설정이다.
+func DefaultSetting(companyID uint) models.ApprovalSetting {
+	return models.ApprovalSetting{
+		CompanyID:      companyID,
+		DigestEnabled:  true,
+		ReminderDays:   DefaultReminderDays,
+		EscalationDays: DefaultEscalationDays,
+	}
+}
+
+// LoadSetting 은 회사의 결재 알림 설정이다. 저장된 설정이 없으면 DefaultSetting 을 반환한다.
+func LoadSetting(tx *gorm.DB, companyID uint) (models.ApprovalSetting, error) {
+	var settings []models.ApprovalSetting
+	if err := tx.Where("company_id = ?", companyID).Limit(1).Find(&settings).Error; err != nil {
+		return models.ApprovalSetting{}, err
+	}
+	if len(settings) == 0 {
+		return DefaultSetting(companyID), nil
+	}
+	return settings[0], nil
+}
+
+// ValidSetting 은 관리자 보고가 결재자 독촉보다 늦게 가는지 확인한다. 0 은 보내지 않는다는 뜻이다.
+func ValidSetting(setting models.ApprovalSetting) bool {
+	if setting.ReminderDays < 0 || setting.EscalationDays < 0 {
+		return false
+	}
+	return setting.ReminderDays == 0 || setting.EscalationDays == 0 || setting.EscalationDays > setting.ReminderDays
+}
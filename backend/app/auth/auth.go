@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/enums"
 	"cywell.com/vacation-promotion/app/models"
 	"cywell.com/vacation-promotion/app/utils"
 	"cywell.com/vacation-promotion/database"
@@ -31,6 +32,26 @@ func AuthCheckMiddleware(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// AdminCheckMiddleware 는 관리자(MemberAdmin)로 등록된 멤버만 통과시킨다. AuthCheckMiddleware 뒤에 둔다.
+func AdminCheckMiddleware(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, ok := CurrentMemberID(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+		var count int64
+		if err := db.DB.Model(&models.MemberAdmin{}).
+			Where("member_id = ? AND admin_type_id = ?", memberID, enums.AdminTypeManager).
+			Count(&count).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if count == 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "관리자만 사용할 수 있습니다"})
+		}
+		return c.Next()
+	}
+}
+
 func GetCorrectMember(loginRequest dto.LoginRequest, c *fiber.Ctx, db *database.Database) (models.Member, error) {
 	var member models.Member
 	//db에서 사용자 정보 가져오기
@@ -128,3 +149,13 @@ func DestorySessionAndToken(c *fiber.Ctx) {
 		SameSite:    "Lax",
 	})
 }
+
+// CurrentMemberID 는 로그인한 멤버의 ID 를 세션에서 가져온다.
+func CurrentMemberID(c *fiber.Ctx) (uint, bool) {
+	session, err := SessionStore.Get(c)
+	if err != nil {
+		return 0, false
+	}
+	memberID, ok := session.Get("member_id").(uint)
+	return memberID, ok
+}
@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+type Holiday struct {
+	Date       time.Time
+	Name       string
+	Substitute bool // 대체공휴일 여부
+}
+
+// Calendar 는 법정 공휴일과 관리자가 추가한 공휴일을 기준으로 근무일을 계산한다.
+type Calendar struct {
+	extra map[string]string
+	years map[int]map[string]string
+}
+
+// New 는 법정 공휴일에 extra 를 더한 Calendar 를 만든다.
+func New(extra []Holiday) *Calendar {
+	cal := &Calendar{
+		extra: make(map[string]string, len(extra)),
+		years: make(map[int]map[string]string),
+	}
+	for _, holiday := range extra {
+		cal.extra[dateKey(holiday.Date)] = holiday.Name
+	}
+	return cal
+}
+
+// Load 는 DB 에 등록된 공휴일(선거일 등)과 companyID 회사의 휴무일을 포함한 Calendar 를 만든다.
+// companyID 가 0 이면 회사 휴무일은 제외한다.
+func Load(tx *gorm.DB, companyID uint) (*Calendar, error) {
+	var publicHolidays []models.PublicHoliday
+	if err := tx.Find(&publicHolidays).Error; err != nil {
+		return nil, err
+	}
+
+	var companyHolidays []models.CompanyHoliday
+	if companyID != 0 {
+		if err := tx.Where("company_id = ?", companyID).Find(&companyHolidays).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	extra := make([]Holiday, 0, len(publicHolidays)+len(companyHolidays))
+	for _, publicHoliday := range publicHolidays {
+		extra = append(extra, Holiday{Date: publicHoliday.Date, Name: publicHoliday.Name})
+	}
+	for _, companyHoliday := range companyHolidays {
+		extra = append(extra, Holiday{Date: companyHoliday.Date, Name: companyHoliday.Name})
+	}
+	return New(extra), nil
+}
+
+// HolidayName 은 date 가 공휴일이면 이름과 true 를 반환한다.
+func (c *Calendar) HolidayName(date time.Time) (string, bool) {
+	key := dateKey(date)
+	if name, ok := c.extra[key]; ok {
+		return name, true
+	}
+	name, ok := c.statutory(date.Year())[key]
+	return name, ok
+}
+
+func (c *Calendar) IsWorkingDay(date time.Time) bool {
+	if isWeekend(date) {
+		return false
+	}
+	_, holiday := c.HolidayName(date)
+	return !holiday
+}
+
+// WorkingDays 는 start 부터 end 까지(양 끝 포함) 근무일수를 계산한다.
+// halfFirst, halfLast 는 첫날, 마지막날을 반일로 계산한다. 하루짜리 기간은 halfFirst 만 본다.
+func (c *Calendar) WorkingDays(start, end time.Time, halfFirst, halfLast bool) float32 {
+	first := DateOnly(start)
+	last := DateOnly(end)
+
+	var days float32
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		if !c.IsWorkingDay(date) {
+			continue
+		}
+		switch {
+		case date.Equal(first) && halfFirst, date.Equal(last) && halfLast && last.After(first):
+			days += 0.5
+		default:
+			days++
+		}
+	}
+	return days
+}
+
+// Holidays 는 from 부터 to 까지(양 끝 포함) 주말을 제외한 공휴일을 날짜순으로 반환한다.
+func (c *Calendar) Holidays(from, to time.Time) []Holiday {
+	first := DateOnly(from)
+	last := DateOnly(to)
+
+	holidays := make([]Holiday, 0)
+	for year := first.Year(); year <= last.Year(); year++ {
+		for _, holiday := range koreanHolidays(year) {
+			if !holiday.Date.Before(first) && !holiday.Date.After(last) {
+				holidays = append(holidays, holiday)
+			}
+		}
+	}
+	for key, name := range c.extra {
+		date, _ := time.Parse("2006-01-02", key)
+		if !date.Before(first) && !date.After(last) {
+			holidays = append(holidays, Holiday{Date: date, Name: name})
+		}
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays
+}
+
+func (c *Calendar) statutory(year int) map[string]string {
+	if holidays, ok := c.years[year]; ok {
+		return holidays
+	}
+	holidays := make(map[string]string)
+	for _, holiday := range koreanHolidays(year) {
+		if _, ok := holidays[dateKey(holiday.Date)]; !ok {
+			holidays[dateKey(holiday.Date)] = holiday.Name
+		}
+	}
+	c.years[year] = holidays
+	return holidays
+}
+
+// 음력 공휴일이 없는 연도를 이미 기록했는지
+var unknownLunarYears sync.Map
+
+// koreanHolidays 는 근무일 계산에 쓰는 법정 공휴일이다. 음력 공휴일이 빠진 연도는 연도마다 한 번 기록한다.
+func koreanHolidays(year int) []Holiday {
+	holidays, err := KoreanHolidays(year)
+	if err != nil {
+		if _, logged := unknownLunarYears.LoadOrStore(year, true); !logged {
+			log.Printf("calendar: %v", err)
+		}
+	}
+	return holidays
+}
+
+func isWeekend(date time.Time) bool {
+	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+}
+
+func dateKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// DateOnly 는 t 의 날짜를 UTC 자정으로 반환한다. 날짜만 비교하는 곳은 시간대가 섞여도 어긋나지 않도록 이 함수를 쓴다.
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
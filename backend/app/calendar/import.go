@@ -0,0 +1,266 @@
+package calendar
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 한 번에 가져올 수 있는 최대 휴무일 수. 잘못된 기간의 일정이 수만 일로 펼쳐지는 것을 막는다.
+const maxImportDays = 1000
+
+var ErrTooManyDays = fmt.Errorf("한 번에 %d일을 초과하는 휴무일을 가져올 수 없습니다", maxImportDays)
+
+// ParseICS 는 iCalendar 파일의 VEVENT 를 휴무일로 변환한다.
+// 여러 날에 걸친 일정은 하루씩 펼친다. 날짜 형식의 DTEND 는 포함하지 않고, 날짜시간 형식의 DTEND 는
+// 자정이 아니면 그 날짜까지 포함한다.
+// 반복 일정은 매년 반복(FREQ=YEARLY)만 펼치며 그 외 반복 규칙은 오류로 처리한다.
+func ParseICS(r io.Reader) ([]Holiday, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []Holiday
+	var inEvent, endsWithinDay bool
+	var start, end time.Time
+	var summary, rrule string
+	var exdates map[string]bool
+	for _, line := range lines {
+		name, params, value := splitICSLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end, summary, rrule, endsWithinDay = time.Time{}, time.Time{}, "", "", false
+			exdates = make(map[string]bool)
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			if start.IsZero() {
+				return nil, errors.New("DTSTART 가 없는 일정이 있습니다")
+			}
+			if !end.IsZero() && endsWithinDay {
+				end = end.AddDate(0, 0, 1)
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			days := int(end.Sub(start).Hours() / 24)
+
+			occurrences := []time.Time{start}
+			if rrule != "" {
+				rule, err := parseYearlyRule(rrule, start)
+				if err != nil {
+					return nil, err
+				}
+				occurrences = rule.occurrences(start)
+			}
+			for _, occurrence := range occurrences {
+				if exdates[dateKey(occurrence)] {
+					continue
+				}
+				for i := 0; i < days; i++ {
+					holidays = append(holidays, Holiday{Date: occurrence.AddDate(0, 0, i), Name: summary})
+					if len(holidays) > maxImportDays {
+						return nil, ErrTooManyDays
+					}
+				}
+			}
+		case !inEvent:
+		case name == "DTSTART":
+			if start, _, err = parseICSDate(params, value); err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			if end, endsWithinDay, err = parseICSDate(params, value); err != nil {
+				return nil, err
+			}
+		case name == "RRULE":
+			rrule = value
+		case name == "EXDATE":
+			for _, exValue := range strings.Split(value, ",") {
+				exdate, _, err := parseICSDate(params, exValue)
+				if err != nil {
+					return nil, err
+				}
+				exdates[dateKey(exdate)] = true
+			}
+		case name == "SUMMARY":
+			summary = unescapeICSText(value)
+		}
+	}
+	return holidays, nil
+}
+
+// 종료 조건(COUNT, UNTIL)이 없는 매년 반복 일정은 시작 연도부터 이만큼만 펼친다.
+const maxRecurYears = 10
+
+// yearlyRule 은 RRULE 중 지원하는 매년 반복 규칙이다.
+type yearlyRule struct {
+	interval int
+	count    int
+	until    time.Time
+}
+
+// "FREQ=YEARLY;INTERVAL=1;COUNT=5" 형식을 해석한다. BYMONTH, BYMONTHDAY 는 DTSTART 와 같을 때만 허용한다.
+func parseYearlyRule(value string, start time.Time) (yearlyRule, error) {
+	rule := yearlyRule{interval: 1}
+	unsupported := fmt.Errorf("지원하지 않는 반복 규칙입니다. 매년 반복(FREQ=YEARLY)만 가져올 수 있습니다: %s", value)
+	var yearly bool
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			yearly = strings.ToUpper(val) == "YEARLY"
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, unsupported
+			}
+			rule.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rule, unsupported
+			}
+			rule.count = n
+		case "UNTIL":
+			until, _, err := parseICSDate("", val)
+			if err != nil {
+				return rule, err
+			}
+			rule.until = until
+		case "BYMONTH":
+			if val != strconv.Itoa(int(start.Month())) {
+				return rule, unsupported
+			}
+		case "BYMONTHDAY":
+			if val != strconv.Itoa(start.Day()) {
+				return rule, unsupported
+			}
+		case "WKST":
+		default:
+			return rule, unsupported
+		}
+	}
+	if !yearly {
+		return rule, unsupported
+	}
+	return rule, nil
+}
+
+// occurrences 는 start 부터 반복되는 시작일이다. 2월 29일처럼 없는 날짜인 해는 건너뛴다.
+func (rule yearlyRule) occurrences(start time.Time) []time.Time {
+	lastYear := start.Year() + maxRecurYears - 1
+	var occurrences []time.Time
+	for years := 0; ; years += rule.interval {
+		date := start.AddDate(years, 0, 0)
+		if !rule.until.IsZero() && date.After(rule.until) {
+			break
+		}
+		if rule.until.IsZero() && rule.count == 0 && date.Year() > lastYear {
+			break
+		}
+		if date.Day() != start.Day() {
+			continue
+		}
+		occurrences = append(occurrences, date)
+		if len(occurrences) == rule.count || len(occurrences) > maxImportDays {
+			break
+		}
+	}
+	return occurrences
+}
+
+// ParseCSV 는 "날짜,이름" 형식의 CSV 를 휴무일로 변환한다. 날짜는 2006-01-02 형식이고 첫 줄 헤더는 있어도 된다.
+func ParseCSV(r io.Reader) ([]Holiday, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := make([]Holiday, 0, len(records))
+	for i, record := range records {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimPrefix(strings.TrimSpace(record[0]), "\ufeff"))
+		if err != nil {
+			if i == 0 {
+				continue // 헤더
+			}
+			return nil, fmt.Errorf("%d번째 줄의 날짜 형식이 잘못되었습니다: %s", i+1, record[0])
+		}
+
+		var name string
+		if len(record) > 1 {
+			name = strings.TrimSpace(record[1])
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: name})
+		if len(holidays) > maxImportDays {
+			return nil, ErrTooManyDays
+		}
+	}
+	return holidays, nil
+}
+
+// 75자를 넘어 접힌 줄(공백이나 탭으로 시작)을 앞 줄에 이어 붙인다.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// "DTSTART;VALUE=DATE:20250101" 를 이름, 파라미터, 값으로 나눈다.
+func splitICSLine(line string) (string, string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	name, params, _ := strings.Cut(head, ";")
+	return strings.ToUpper(name), params, value
+}
+
+// 날짜(20250101) 와 날짜시간(20250101T090000, 20250101T000000Z) 형식 모두 날짜만 사용한다.
+// 날짜시간이 자정이 아니면 그날 중의 시각이므로 true 를 함께 반환한다.
+func parseICSDate(params, value string) (time.Time, bool, error) {
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("잘못된 날짜 형식입니다: %s", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("잘못된 날짜 형식입니다: %s", value)
+	}
+	for _, param := range strings.Split(params, ";") {
+		if strings.EqualFold(param, "VALUE=DATE") {
+			return date, false, nil
+		}
+	}
+	instant, err := time.Parse("20060102T150405", strings.TrimSuffix(value, "Z"))
+	if err != nil {
+		return date, false, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		// UTC 시각은 한국 시간 기준 날짜로 바꾼다
+		instant = instant.In(time.FixedZone("KST", 9*60*60))
+	}
+	withinDay := instant.Hour() != 0 || instant.Minute() != 0 || instant.Second() != 0
+	return DateOnly(instant), withinDay, nil
+}
+
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+)
+
+func parseDates(t *testing.T, ics string) []string {
+	t.Helper()
+	holidays, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dates := make([]string, 0, len(holidays))
+	for _, holiday := range holidays {
+		dates = append(dates, dateKey(holiday.Date))
+	}
+	return dates
+}
+
+func event(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestParseICS(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		want []string
+	}{
+		{
+			name: "날짜 DTEND 는 포함하지 않음",
+			ics:  event("DTSTART;VALUE=DATE:20250505", "DTEND;VALUE=DATE:20250507", "SUMMARY:창립기념일"),
+			want: []string{"2025-05-05", "2025-05-06"},
+		},
+		{
+			name: "날짜시간 DTEND 는 그 날짜까지 포함",
+			ics:  event("DTSTART:20250505T090000", "DTEND:20250506T180000", "SUMMARY:워크숍"),
+			want: []string{"2025-05-05", "2025-05-06"},
+		},
+		{
+			name: "자정에 끝나는 날짜시간 DTEND 는 포함하지 않음",
+			ics:  event("DTSTART:20250505T000000", "DTEND:20250506T000000", "SUMMARY:워크숍"),
+			want: []string{"2025-05-05"},
+		},
+		{
+			name: "UTC 시각은 한국 시간 기준",
+			ics:  event("DTSTART:20250504T150000Z", "DTEND:20250505T150000Z", "SUMMARY:휴무"),
+			want: []string{"2025-05-05"},
+		},
+		{
+			name: "매년 반복",
+			ics:  event("DTSTART;VALUE=DATE:20250505", "RRULE:FREQ=YEARLY;COUNT=3", "SUMMARY:창립기념일"),
+			want: []string{"2025-05-05", "2026-05-05", "2027-05-05"},
+		},
+		{
+			name: "매년 반복 UNTIL 과 EXDATE",
+			ics:  event("DTSTART;VALUE=DATE:20250505", "RRULE:FREQ=YEARLY;UNTIL=20280101", "EXDATE;VALUE=DATE:20260505", "SUMMARY:창립기념일"),
+			want: []string{"2025-05-05", "2027-05-05"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDates(t, tt.ics)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ParseICS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseICSYearlyWithoutEnd(t *testing.T) {
+	got := parseDates(t, event("DTSTART;VALUE=DATE:20250505", "RRULE:FREQ=YEARLY", "SUMMARY:창립기념일"))
+	if len(got) != maxRecurYears || got[len(got)-1] != "2034-05-05" {
+		t.Errorf("ParseICS() = %v, want %d years through 2034", got, maxRecurYears)
+	}
+}
+
+func TestParseICSUnsupportedRule(t *testing.T) {
+	for _, rrule := range []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "RRULE:FREQ=YEARLY;BYMONTH=6", "RRULE:FREQ=YEARLY;BYDAY=1MO"} {
+		if _, err := ParseICS(strings.NewReader(event("DTSTART;VALUE=DATE:20250505", rrule))); err == nil {
+			t.Errorf("%s: expected error", rrule)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 대체공휴일 적용 기준
+type substituteRule int
+
+const (
+	substituteNone    substituteRule = iota
+	substituteSunday                 // 일요일 또는 다른 공휴일과 겹칠 때 (설날, 추석)
+	substituteWeekend                // 토요일, 일요일 또는 다른 공휴일과 겹칠 때
+)
+
+type statutoryHoliday struct {
+	name  string
+	dates []time.Time
+	rule  substituteRule
+}
+
+// 음력 공휴일의 양력 날짜 (한국천문연구원 월력요항 기준). month, day 순서로 설날, 부처님오신날, 추석
+// 등록되지 않은 연도는 음력 공휴일을 근무일로 계산하므로 매년 월력요항이 발표되면 다음 연도를 추가해야 한다.
+var lunarHolidays = map[int][3][2]int{
+	2020: {{1, 25}, {4, 30}, {10, 1}},
+	2021: {{2, 12}, {5, 19}, {9, 21}},
+	2022: {{2, 1}, {5, 8}, {9, 10}},
+	2023: {{1, 22}, {5, 27}, {9, 29}},
+	2024: {{2, 10}, {5, 15}, {9, 17}},
+	2025: {{1, 29}, {5, 5}, {10, 6}},
+	2026: {{2, 17}, {5, 24}, {9, 25}},
+	2027: {{2, 7}, {5, 13}, {9, 15}},
+	2028: {{1, 26}, {5, 2}, {10, 3}},
+	2029: {{2, 13}, {5, 20}, {9, 22}},
+	2030: {{2, 3}, {5, 9}, {9, 12}},
+}
+
+// 대체공휴일 확대 시행일
+var (
+	nationalDaySubstituteFrom = time.Date(2021, 8, 4, 0, 0, 0, 0, time.UTC) // 삼일절, 광복절, 개천절, 한글날
+	buddhaXmasSubstituteFrom  = time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC) // 부처님오신날, 성탄절
+)
+
+var ErrLunarYearUnknown = errors.New("음력 공휴일이 등록되지 않은 연도입니다")
+
+// KoreanHolidays 는 year 의 법정 공휴일과 대체공휴일을 날짜순으로 반환한다.
+// lunarHolidays 에 없는 연도는 양력 공휴일만 반환하고 ErrLunarYearUnknown 을 함께 반환한다.
+// 선거일 등 임시 공휴일은 PublicHoliday 로 관리한다.
+func KoreanHolidays(year int) ([]Holiday, error) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	ruleFrom := func(from time.Time, d time.Time) substituteRule {
+		if d.Before(from) {
+			return substituteNone
+		}
+		return substituteWeekend
+	}
+
+	holidays := []statutoryHoliday{
+		{name: "신정", dates: []time.Time{date(1, 1)}},
+		{name: "삼일절", dates: []time.Time{date(3, 1)}, rule: ruleFrom(nationalDaySubstituteFrom, date(3, 1))},
+		{name: "근로자의 날", dates: []time.Time{date(5, 1)}},
+		{name: "어린이날", dates: []time.Time{date(5, 5)}, rule: substituteWeekend},
+		{name: "현충일", dates: []time.Time{date(6, 6)}},
+		{name: "광복절", dates: []time.Time{date(8, 15)}, rule: ruleFrom(nationalDaySubstituteFrom, date(8, 15))},
+		{name: "개천절", dates: []time.Time{date(10, 3)}, rule: ruleFrom(nationalDaySubstituteFrom, date(10, 3))},
+		{name: "한글날", dates: []time.Time{date(10, 9)}, rule: ruleFrom(nationalDaySubstituteFrom, date(10, 9))},
+		{name: "성탄절", dates: []time.Time{date(12, 25)}, rule: ruleFrom(buddhaXmasSubstituteFrom, date(12, 25))},
+	}
+
+	lunar, ok := lunarHolidays[year]
+	if ok {
+		seollal := date(time.Month(lunar[0][0]), lunar[0][1])
+		buddha := date(time.Month(lunar[1][0]), lunar[1][1])
+		chuseok := date(time.Month(lunar[2][0]), lunar[2][1])
+		holidays = append(holidays,
+			statutoryHoliday{name: "설날", dates: aroundDay(seollal), rule: substituteSunday},
+			statutoryHoliday{name: "부처님오신날", dates: []time.Time{buddha}, rule: ruleFrom(buddhaXmasSubstituteFrom, buddha)},
+			statutoryHoliday{name: "추석", dates: aroundDay(chuseok), rule: substituteSunday},
+		)
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].dates[0].Before(holidays[j].dates[0]) })
+	if !ok {
+		return withSubstitutes(holidays), fmt.Errorf("%w: %d", ErrLunarYearUnknown, year)
+	}
+	return withSubstitutes(holidays), nil
+}
+
+// 전날, 당일, 다음날
+func aroundDay(day time.Time) []time.Time {
+	return []time.Time{day.AddDate(0, 0, -1), day, day.AddDate(0, 0, 1)}
+}
+
+// withSubstitutes 는 날짜순으로 정렬된 공휴일에 대체공휴일을 더한다.
+// 겹치는 공휴일은 나중에 처리되는 공휴일 쪽에서 한 번만 대체일을 만든다.
+func withSubstitutes(holidays []statutoryHoliday) []Holiday {
+	occupied := make(map[string]bool)
+	for _, holiday := range holidays {
+		for _, d := range holiday.dates {
+			occupied[dateKey(d)] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	result := make([]Holiday, 0, len(holidays)+4)
+	for _, holiday := range holidays {
+		needed := 0
+		for _, d := range holiday.dates {
+			key := dateKey(d)
+			if holiday.rule != substituteNone {
+				switch {
+				case seen[key]:
+					needed++
+				case d.Weekday() == time.Sunday:
+					needed++
+				case d.Weekday() == time.Saturday && holiday.rule == substituteWeekend:
+					needed++
+				}
+			}
+			seen[key] = true
+			result = append(result, Holiday{Date: d, Name: holiday.name})
+		}
+
+		next := holiday.dates[len(holiday.dates)-1]
+		for ; needed > 0; needed-- {
+			next = next.AddDate(0, 0, 1)
+			for isWeekend(next) || occupied[dateKey(next)] {
+				next = next.AddDate(0, 0, 1)
+			}
+			occupied[dateKey(next)] = true
+			seen[dateKey(next)] = true
+			result = append(result, Holiday{Date: next, Name: "대체공휴일(" + holiday.name + ")", Substitute: true})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date.Before(result[j].Date) })
+	return result
+}
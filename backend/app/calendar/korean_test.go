@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKoreanHolidays(t *testing.T) {
+	tests := []struct {
+		year  int
+		date  string
+		name  string
+		found bool
+	}{
+		{2024, "2024-02-09", "설날", true},
+		{2024, "2024-02-11", "설날", true},
+		{2024, "2024-02-12", "대체공휴일(설날)", true},   // 설 연휴가 일요일과 겹침
+		{2024, "2024-05-06", "대체공휴일(어린이날)", true}, // 어린이날이 일요일
+		{2024, "2024-05-15", "부처님오신날", true},
+		{2024, "2024-09-17", "추석", true},
+		{2025, "2025-03-03", "대체공휴일(삼일절)", true},    // 삼일절이 토요일
+		{2025, "2025-05-06", "대체공휴일(부처님오신날)", true}, // 어린이날과 부처님오신날이 겹침
+		{2025, "2025-10-08", "대체공휴일(추석)", true},     // 추석 연휴가 일요일과 겹침
+		{2020, "2020-08-17", "", false},             // 2021-08-04 이전 광복절은 대체공휴일 없음
+		{2022, "2022-12-26", "", false},             // 2023-05-04 이전 성탄절은 대체공휴일 없음
+		{2027, "2027-12-27", "대체공휴일(성탄절)", true},    // 성탄절이 토요일
+		{2028, "2028-01-25", "설날", true},
+		{2028, "2028-01-26", "설날", true},
+		{2028, "2028-01-27", "설날", true},
+		{2028, "2028-01-28", "", false},
+	}
+	for _, tt := range tests {
+		holidays, err := KoreanHolidays(tt.year)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make(map[string]string)
+		for _, holiday := range holidays {
+			names[holiday.Date.Format("2006-01-02")] = holiday.Name
+		}
+		name, found := names[tt.date]
+		if found != tt.found || name != tt.name {
+			t.Errorf("%s = %q (%v), want %q (%v)", tt.date, name, found, tt.name, tt.found)
+		}
+	}
+}
+
+func TestKoreanHolidaysSorted(t *testing.T) {
+	holidays, err := KoreanHolidays(2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(holidays); i++ {
+		if holidays[i].Date.Before(holidays[i-1].Date) {
+			t.Fatalf("%s comes after %s", holidays[i].Date.Format("2006-01-02"), holidays[i-1].Date.Format("2006-01-02"))
+		}
+	}
+	if len(holidays) == 0 || !holidays[0].Date.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first holiday = %v, want 2025-01-01", holidays)
+	}
+}
+
+func TestKoreanHolidaysUnknownLunarYear(t *testing.T) {
+	holidays, err := KoreanHolidays(2031)
+	if !errors.Is(err, ErrLunarYearUnknown) {
+		t.Errorf("KoreanHolidays(2031) error = %v, want %v", err, ErrLunarYearUnknown)
+	}
+	for _, holiday := range holidays {
+		if holiday.Name == "설날" || holiday.Name == "추석" {
+			t.Errorf("unexpected lunar holiday %s on %s", holiday.Name, holiday.Date.Format("2006-01-02"))
+		}
+	}
+	if len(holidays) == 0 {
+		t.Error("solar holidays should still be returned")
+	}
+}
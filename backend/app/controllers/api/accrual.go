@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// 회사 전체 멤버의 누락된 휴가 지급분 생성
+func AccrueCompanyVacationsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		created, err := accrual.NewEngine(db, time.Now).AccrueCompany(uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapGivenVacationsToResponse(created))
+	}
+}
+
+// 멤버 한 명의 누락된 휴가 지급분 생성
+func AccrueMemberVacationsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		created, err := accrual.NewEngine(db, time.Now).AccrueMember(uint(memberID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapGivenVacationsToResponse(created))
+	}
+}
+
+// 퇴직 정산 조회. daily_wage 쿼리로 통상임금 일급을 받는다.
+func GetRetirementSettlementHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		dailyWage, err := strconv.ParseInt(c.Query("daily_wage", "0"), 10, 64)
+		if err != nil || dailyWage < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid daily wage"})
+		}
+
+		settlement, err := accrual.NewEngine(db, time.Now).Settle(uint(memberID), dailyWage)
+		if err != nil {
+			return settlementError(c, err)
+		}
+
+		return c.JSON(mapSettlementToResponse(settlement, nil))
+	}
+}
+
+// 퇴직 정산. apply 가 true 이면 부족분만큼 보전 지급분을 생성한다.
+func SettleRetirementHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		var request dto.RetirementSettlementRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		engine := accrual.NewEngine(db, time.Now)
+		if !request.Apply {
+			settlement, err := engine.Settle(uint(memberID), request.DailyWage)
+			if err != nil {
+				return settlementError(c, err)
+			}
+			return c.JSON(mapSettlementToResponse(settlement, nil))
+		}
+
+		settlement, created, err := engine.ApplySettlement(uint(memberID), request.DailyWage)
+		if err != nil {
+			return settlementError(c, err)
+		}
+		return c.JSON(mapSettlementToResponse(settlement, created))
+	}
+}
+
+func settlementError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, accrual.ErrNotRetired) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+func mapSettlementToResponse(settlement accrual.Settlement, created *models.GivenVacation) dto.RetirementSettlementResponse {
+	response := dto.RetirementSettlementResponse{
+		MemberID:     settlement.MemberID,
+		GrantedDays:  settlement.GrantedDays,
+		SettledDays:  settlement.SettledDays,
+		HireDateDays: settlement.HireDateDays,
+		DiffDays:     settlement.DiffDays,
+		DailyWage:    settlement.DailyWage,
+		Allowance:    settlement.Allowance,
+	}
+	if created != nil {
+		grant := dto.MapGivenVacationToResponse(*created)
+		response.CorrectiveGrant = &grant
+	}
+	return response
+}
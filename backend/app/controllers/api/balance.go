@@ -0,0 +1,132 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 소멸 임박으로 표시하는 기간
+const expiringSoonDays = 30
+
+// 멤버 잔여 휴가 조회. year 에 유효했던 지급분별 잔여일수와 승인 대기 중인 계획을 함께 반환한다.
+func GetMemberBalanceHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		now := time.Now()
+		year := now.UTC().Year()
+		if yearStr := c.Query("year"); yearStr != "" {
+			year, err = strconv.Atoi(yearStr)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid year"})
+			}
+		}
+		// 지급일, 소멸일은 UTC 날짜로 저장하므로 연도 경계도 UTC 로 잡는다
+		startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		endDate := startDate.AddDate(1, 0, 0)
+
+		var grants []models.GivenVacation
+		if err := db.DB.
+			Where("member_id = ? AND generate_date < ? AND expire_date > ?", memberID, endDate, startDate).
+			Order("expire_date ASC, generate_date ASC").
+			Find(&grants).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		givenVacationIDs := make([]uint, 0, len(grants))
+		for _, grant := range grants {
+			givenVacationIDs = append(givenVacationIDs, grant.ID)
+		}
+		reservedByPlan, err := ledger.ReservedByPlan(db.DB, givenVacationIDs)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		response := dto.MemberBalanceResponse{
+			MemberID:     uint(memberID),
+			Year:         year,
+			Grants:       make([]dto.GrantBalanceResponse, 0, len(grants)),
+			PendingPlans: make([]dto.VacationPlanResponse, 0),
+		}
+
+		expiringSoonDate := now.AddDate(0, 0, expiringSoonDays)
+		pendingPlanIDs := make(map[uint]bool)
+		for _, grant := range grants {
+			expired := grant.IsExpired || !grant.ExpireDate.After(now)
+			grantResponse := dto.GrantBalanceResponse{
+				GivenVacationID: grant.ID,
+				GenerateRule:    grant.GenerateRule,
+				GenerateNote:    grant.GenerateNote,
+				GenerateDate:    grant.GenerateDate,
+				ExpireDate:      grant.ExpireDate,
+				IsExpired:       expired,
+				ExpiringSoon:    !expired && grant.ExpireDate.Before(expiringSoonDate) && grant.RemainingDays > 0,
+				GivenDays:       grant.GivenDays,
+				UsedDays:        grant.UsedDays,
+				ReservedDays:    grant.ReservedDays,
+				RemainingDays:   grant.RemainingDays,
+				PendingPlans:    make([]dto.PendingPlanResponse, 0),
+			}
+			for planID, days := range reservedByPlan[grant.ID] {
+				grantResponse.PendingPlans = append(grantResponse.PendingPlans, dto.PendingPlanResponse{VacationPlanID: planID, ReservedDays: days})
+				pendingPlanIDs[planID] = true
+			}
+			sort.Slice(grantResponse.PendingPlans, func(i, j int) bool {
+				return grantResponse.PendingPlans[i].VacationPlanID < grantResponse.PendingPlans[j].VacationPlanID
+			})
+
+			response.GrantedDays += grant.GivenDays
+			response.UsedDays += grant.UsedDays
+			response.ReservedDays += grant.ReservedDays
+			if !expired {
+				response.RemainingDays += grant.RemainingDays
+			}
+			if grantResponse.ExpiringSoon {
+				response.ExpiringSoonDays += grant.RemainingDays
+			}
+			response.Grants = append(response.Grants, grantResponse)
+		}
+
+		if len(pendingPlanIDs) > 0 {
+			planIDs := make([]uint, 0, len(pendingPlanIDs))
+			for planID := range pendingPlanIDs {
+				planIDs = append(planIDs, planID)
+			}
+
+			var plans []models.VacationPlan
+			if err := db.DB.
+				Preload("Member").
+				Preload("ApplyVacations").
+				Preload("ApproverOrders").
+				Preload("ApproverOrders.Member").
+				Where("id IN ?", planIDs).
+				Order("apply_date ASC").
+				Find(&plans).Error; err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+
+			for _, plan := range plans {
+				vacationPlanResponse := dto.MapVacationPlanToResponse(plan)
+				for _, vacation := range plan.ApplyVacations {
+					vacationPlanResponse.Vacations = append(vacationPlanResponse.Vacations, dto.MapApplyVacationToResponse(vacation))
+				}
+				for _, ApproveOrder := range plan.ApproverOrders {
+					vacationPlanResponse.ApproverOrder = append(vacationPlanResponse.ApproverOrder, dto.MapApproverOrderToResponse(ApproveOrder))
+				}
+				response.PendingPlans = append(response.PendingPlans, vacationPlanResponse)
+			}
+		}
+
+		return c.JSON(response)
+	}
+}
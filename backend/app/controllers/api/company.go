@@ -2,12 +2,18 @@ package api
 
 import (
 	"errors"
+	"strconv"
 
+	"cywell.com/vacation-promotion/app/approval"
 	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/expiry"
 	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/promotion"
 	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func CreateCompany(tx *gorm.DB, company models.Company) (*models.Company, *models.Organize, error) {
@@ -66,6 +72,10 @@ func GetCompanyHandler(db *database.Database) fiber.Handler {
 		if err := db.DB.Preload("VacationGenerateType").First(&company, id).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
+		setting, err := promotion.LoadSetting(db.DB, company.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
 		// company를 companyResponse형으로 변환
 		companyResponse := dto.CompanyResponse{
 			ID:                          company.ID,
@@ -73,6 +83,10 @@ func GetCompanyHandler(db *database.Database) fiber.Handler {
 			AccountingDay:               company.AccountingDay,
 			VacationGenerateTypeName:    company.VacationGenerateType.TypeName,
 			VacationGenerateDescription: company.VacationGenerateType.Description,
+			AdvanceVacationDays:         company.AdvanceVacationDays,
+			CarryOverPolicy:             company.CarryOverPolicy,
+			CarryOverMaxDays:            company.CarryOverMaxDays,
+			PromotionSetting:            dto.MapPromotionSettingToResponse(setting),
 		}
 
 		return c.JSON(companyResponse)
@@ -86,16 +100,167 @@ func UpdateCompanyHandler(db *database.Database) fiber.Handler {
 		if err := db.DB.Preload("VacationGenerateType").First(&company, id).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
+		// 미리 당겨쓰기 일수는 검증하는 advance-vacation API 로만 바꾼다
+		advanceDays := company.AdvanceVacationDays
 		if err := c.BodyParser(&company); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
-		if err := db.DB.Save(&company).Error; err != nil {
+		company.AdvanceVacationDays = advanceDays
+		if !expiry.ValidPolicy(company.CarryOverPolicy) || company.CarryOverMaxDays < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid carry over policy"})
+		}
+		// 촉진 설정 등 연관 설정은 각각 검증하는 API 로만 바꾼다
+		if err := db.DB.Omit(clause.Associations).Save(&company).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err := db.DB.Preload("VacationGenerateType").First(&company, company.ID).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(company)
 	}
 }
 
+// 미리 당겨쓰기 허용 일수 변경. 이미 예약한 휴가는 그대로 두고 이후 신청, 수정부터 적용한다
+func UpdateAdvanceVacationHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.AdvanceVacationRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+		company.AdvanceVacationDays = *request.AdvanceVacationDays
+		if err := db.DB.Model(&company).Update("advance_vacation_days", company.AdvanceVacationDays).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.AdvanceVacationRequest{AdvanceVacationDays: &company.AdvanceVacationDays})
+	}
+}
+
+// 회사 촉진 설정 조회. 저장한 적이 없으면 기본 설정(법정 기한, 사용 시기 자동 제안)
+func GetPromotionSettingHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		setting, err := promotion.LoadSetting(db.DB, uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapPromotionSettingToResponse(setting))
+	}
+}
+
+// 회사 촉진 설정 변경. 촉진 엔진과 스케줄러는 다음 실행부터 바뀐 설정을 따른다
+func UpdatePromotionSettingHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.PromotionSettingRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+
+		setting, err := promotion.LoadSetting(db.DB, company.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		setting.Enabled = *request.Enabled
+		setting.FirstNoticeOffsetDays = request.FirstNoticeOffsetDays
+		setting.SecondNoticeOffsetDays = request.SecondNoticeOffsetDays
+		setting.ResponseDays = request.ResponseDays
+		setting.AutoProposeDates = *request.AutoProposeDates
+		setting.UpdatedBy = actorID(c)
+		if err := db.DB.Save(&setting).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapPromotionSettingToResponse(setting))
+	}
+}
+
+// 회사 결재 설정 조회. 저장한 적이 없으면 기본 설정(매일 요약, 3일 뒤 독촉, 5일 뒤 관리자 보고, 촉진 사용 계획은 결재 필요)
+func GetApprovalSettingHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		setting, err := approval.LoadSetting(db.DB, uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapApprovalSettingToResponse(setting))
+	}
+}
+
+// 회사 결재 설정 변경. 결재 알림 작업은 다음 실행부터 바뀐 설정을 따른다
+func UpdateApprovalSettingHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.ApprovalSettingRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+
+		setting, err := approval.LoadSetting(db.DB, company.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		setting.DigestEnabled = *request.DigestEnabled
+		setting.ReminderDays = request.ReminderDays
+		setting.EscalationDays = request.EscalationDays
+		setting.AutoApprovePromotionPlans = *request.AutoApprovePromotionPlans
+		if !approval.ValidSetting(setting) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "관리자 보고 일수는 결재자 독촉 일수보다 커야 합니다"})
+		}
+		setting.UpdatedBy = actorID(c)
+		if err := db.DB.Save(&setting).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapApprovalSettingToResponse(setting))
+	}
+}
+
 func DeleteCompanyHandler(db *database.Database) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("companyID")
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 회사 휴무일 조회. year 쿼리가 있으면 해당 연도만
+func GetCompanyHolidaysHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		query := db.DB.Where("company_id = ?", companyID).Order("date ASC")
+		if yearStr := c.Query("year"); yearStr != "" {
+			year, err := strconv.Atoi(yearStr)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid year"})
+			}
+			query = query.Where("date BETWEEN ? AND ?",
+				time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC))
+		}
+
+		var holidays []models.CompanyHoliday
+		if err := query.Find(&holidays).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapCompanyHolidaysToResponse(holidays))
+	}
+}
+
+func CreateCompanyHolidayHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.HolidayRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+
+		date, _ := time.Parse("2006-01-02", request.Date)
+		var count int64
+		if err := db.DB.Model(&models.CompanyHoliday{}).Where("company_id = ? AND date = ?", companyID, date).Count(&count).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if count > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Holiday already exists on this date"})
+		}
+
+		holiday := models.CompanyHoliday{CompanyID: company.ID, Date: date, Name: request.Name}
+		if err := db.DB.Create(&holiday).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusCreated).JSON(dto.MapCompanyHolidayToResponse(holiday))
+	}
+}
+
+func UpdateCompanyHolidayHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		holidayID, err := strconv.ParseUint(c.Params("holidayID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid holiday ID"})
+		}
+
+		var request dto.HolidayRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var holiday models.CompanyHoliday
+		if err := db.DB.Where("company_id = ?", companyID).First(&holiday, holidayID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Holiday not found"})
+		}
+
+		date, _ := time.Parse("2006-01-02", request.Date)
+		var count int64
+		if err := db.DB.Model(&models.CompanyHoliday{}).Where("company_id = ? AND date = ? AND id <> ?", companyID, date, holiday.ID).Count(&count).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if count > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Holiday already exists on this date"})
+		}
+
+		holiday.Date = date
+		holiday.Name = request.Name
+		if err := db.DB.Save(&holiday).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapCompanyHolidayToResponse(holiday))
+	}
+}
+
+func DeleteCompanyHolidayHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		holidayID, err := strconv.ParseUint(c.Params("holidayID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid holiday ID"})
+		}
+
+		result := db.DB.Where("company_id = ?", companyID).Delete(&models.CompanyHoliday{}, holidayID)
+		if result.Error != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": result.Error.Error()})
+		}
+		if result.RowsAffected == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Holiday not found"})
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// 회사 휴무일 일괄 등록. multipart file 필드로 .ics 또는 .csv(날짜,이름) 파일을 받는다.
+// 같은 날짜의 휴무일이 이미 있으면 이름을 덮어쓴다.
+func ImportCompanyHolidaysHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		defer file.Close()
+
+		var parsed []calendar.Holiday
+		switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+		case ".ics":
+			parsed, err = calendar.ParseICS(file)
+		case ".csv":
+			parsed, err = calendar.ParseCSV(file)
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Only .ics or .csv files are supported"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// 파일 안에서 같은 날짜가 반복되면 마지막 이름을 사용
+		byDate := make(map[string]int, len(parsed))
+		holidays := make([]models.CompanyHoliday, 0, len(parsed))
+		for _, holiday := range parsed {
+			name := holiday.Name
+			if name == "" {
+				name = "회사 휴무일"
+			}
+			if len([]rune(name)) > 60 {
+				name = string([]rune(name)[:60])
+			}
+			key := holiday.Date.Format("2006-01-02")
+			if i, ok := byDate[key]; ok {
+				holidays[i].Name = name
+				continue
+			}
+			byDate[key] = len(holidays)
+			holidays = append(holidays, models.CompanyHoliday{CompanyID: company.ID, Date: holiday.Date, Name: name})
+		}
+
+		if len(holidays) > 0 {
+			dates := make([]time.Time, 0, len(holidays))
+			for _, holiday := range holidays {
+				dates = append(dates, holiday.Date)
+			}
+			err = db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "company_id"}, {Name: "date"}},
+					DoUpdates: clause.AssignmentColumns([]string{"name"}),
+				}).Create(&holidays).Error; err != nil {
+					return err
+				}
+				// 덮어쓴 행의 ID 를 돌려주기 위해 다시 조회
+				return tx.Where("company_id = ? AND date IN ?", company.ID, dates).Order("date ASC").Find(&holidays).Error
+			})
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		return c.JSON(dto.ImportHolidaysResponse{
+			Imported: len(holidays),
+			Holidays: dto.MapCompanyHolidaysToResponse(holidays),
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/promotion"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 노무수령 거부 통지 목록. memberID, pending(미확인만) 쿼리로 필터
+func GetDenyWorksHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		query := db.DB.Model(&models.VacationDenyWork{}).
+			Joins("JOIN members ON members.id = vacation_deny_works.member_id").
+			Where("members.company_id = ?", companyID).
+			Preload("Member").
+			Order("vacation_deny_works.work_date DESC, vacation_deny_works.id DESC")
+		if memberQ := c.Query("memberID"); memberQ != "" {
+			memberID, err := strconv.ParseUint(memberQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+			}
+			query = query.Where("vacation_deny_works.member_id = ?", memberID)
+		}
+		if c.QueryBool("pending") {
+			query = query.Where("vacation_deny_works.accepted_at IS NULL")
+		}
+
+		var denyWorks []models.VacationDenyWork
+		if err := query.Find(&denyWorks).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapDenyWorksToResponse(denyWorks))
+	}
+}
+
+// 촉진 휴가일에 출근한 멤버에게 노무수령 거부 통지
+func IssueDenyWorkHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		applyVacationID, err := strconv.ParseUint(c.Params("applyVacationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid apply vacation ID"})
+		}
+
+		var request dto.IssueDenyWorkRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		denyWork, err := promotion.NewEngine(db, time.Now).IssueDenyWork(uint(companyID), uint(applyVacationID), request.WorkDate, request.Memo, actorID(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, promotion.ErrVacationNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrNotPromotionVacation), errors.Is(err, promotion.ErrNotVacationDay):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrDenyWorkExists):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(dto.MapDenyWorkToResponse(denyWork))
+	}
+}
+
+// 노무수령 거부 통지 확인. 통지를 받은 본인만 확인할 수 있다
+func AcceptDenyWorkHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		notificationID, err := strconv.ParseUint(c.Params("notificationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID"})
+		}
+		if !isCurrentMember(c, uint(memberID)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인에게 온 통지만 확인할 수 있습니다"})
+		}
+
+		denyWork, err := promotion.NewEngine(db, time.Now).AcceptDenyWork(uint(memberID), uint(notificationID))
+		if err != nil {
+			switch {
+			case errors.Is(err, promotion.ErrNoticeNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrDenyWorkAccepted):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapDenyWorkToResponse(denyWork))
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/evidence"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// 회사 촉진 소명 자료 내보내기. year(지급일 기준, 기본 올해), memberID, format(json, html) 쿼리
+func ExportCompanyEvidenceHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		year := c.QueryInt("year", time.Now().Year())
+		scope := evidence.Scope{CompanyID: uint(companyID), Year: &year}
+		if memberQ := c.Query("memberID"); memberQ != "" {
+			memberID, err := strconv.ParseUint(memberQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+			}
+			id := uint(memberID)
+			scope.MemberID = &id
+		}
+
+		return exportEvidence(c, db, scope)
+	}
+}
+
+// 멤버 촉진 소명 자료 내보내기. year(지급일 기준, 없으면 전체), format(json, html) 쿼리
+func ExportMemberEvidenceHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		var member models.Member
+		if err := db.DB.First(&member, memberID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+		}
+
+		scope := evidence.Scope{CompanyID: member.CompanyID, MemberID: &member.ID}
+		if yearQ := c.Query("year"); yearQ != "" {
+			year, err := strconv.Atoi(yearQ)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid year"})
+			}
+			scope.Year = &year
+		}
+
+		return exportEvidence(c, db, scope)
+	}
+}
+
+// 제출된 소명 자료(JSON)의 해시를 다시 계산해 변조 여부와 내보낸 기록을 확인
+func VerifyEvidenceHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var sealed evidence.Sealed
+		if err := json.Unmarshal(c.Body(), &sealed); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		valid, hash, err := evidence.Verify(sealed)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		response := dto.VerifyEvidenceResponse{Valid: valid, Hash: hash}
+		var record models.PromotionEvidence
+		err = db.DB.Where("company_id = ? AND hash = ?", companyID, hash).Order("id ASC").First(&record).Error
+		switch {
+		case err == nil:
+			response.Recorded = true
+			response.ExportedAt = &record.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// exportEvidence 는 소명 자료를 만들고 해시를 기록한 뒤 요청한 형식으로 내려준다.
+func exportEvidence(c *fiber.Ctx, db *database.Database, scope evidence.Scope) error {
+	format := c.Query("format", "json")
+	if format != "json" && format != "html" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid format"})
+	}
+
+	archive, err := evidence.Build(db.DB, scope, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	sealed, err := evidence.Seal(archive)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	record := models.PromotionEvidence{
+		CompanyID:   scope.CompanyID,
+		MemberID:    scope.MemberID,
+		Year:        scope.Year,
+		GrantCount:  len(archive.Grants),
+		Hash:        sealed.Hash,
+		GeneratedBy: actorID(c),
+	}
+	if err := db.DB.Create(&record).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filename := fmt.Sprintf("promotion-evidence-%d", scope.CompanyID)
+	if scope.MemberID != nil {
+		filename += fmt.Sprintf("-member-%d", *scope.MemberID)
+	}
+	if scope.Year != nil {
+		filename += fmt.Sprintf("-%d", *scope.Year)
+	}
+	c.Set("X-Evidence-Hash", sealed.Hash)
+
+	if format == "html" {
+		body, err := evidence.HTML(archive, sealed)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.html"`, filename))
+		return c.Send(body)
+	}
+
+	body, err := json.Marshal(sealed)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+	return c.Send(body)
+}
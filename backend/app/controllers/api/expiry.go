@@ -0,0 +1,46 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/expiry"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 회사 전체 멤버의 소멸일이 지난 지급분 소멸, 이월 처리
+func ExpireCompanyVacationsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		expiries, err := expiry.NewEngine(db, time.Now).ExpireCompany(uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapVacationExpiriesToResponse(expiries))
+	}
+}
+
+// 멤버의 소멸, 이월 처리 기록 조회
+func GetVacationExpiriesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		var expiries []models.VacationExpiry
+		if err := db.DB.Where("member_id = ?", memberID).Order("expire_date DESC, id DESC").Find(&expiries).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapVacationExpiriesToResponse(expiries))
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 연도별 공휴일 조회 (법정 공휴일, 대체공휴일, 관리자가 추가한 공휴일). company_id 쿼리로 회사 휴무일 포함
+func GetHolidaysHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		year, err := strconv.Atoi(c.Query("year", strconv.Itoa(time.Now().Year())))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid year"})
+		}
+		companyID, err := strconv.ParseUint(c.Query("company_id", "0"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+
+		var publicHolidays []models.PublicHoliday
+		if err := db.DB.Where("date BETWEEN ? AND ?", from, to).Find(&publicHolidays).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// 같은 날짜의 법정 공휴일과 구분하기 위해 날짜와 이름으로 ID 를 찾는다
+		holidayKey := func(date time.Time, name string) string { return date.Format("2006-01-02") + name }
+
+		extra := make([]calendar.Holiday, 0, len(publicHolidays))
+		ids := make(map[string]uint, len(publicHolidays))
+		for _, publicHoliday := range publicHolidays {
+			extra = append(extra, calendar.Holiday{Date: publicHoliday.Date, Name: publicHoliday.Name})
+			ids[holidayKey(publicHoliday.Date, publicHoliday.Name)] = publicHoliday.ID
+		}
+
+		var companyHolidays []models.CompanyHoliday
+		if err := db.DB.Where("company_id = ? AND date BETWEEN ? AND ?", companyID, from, to).Find(&companyHolidays).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		companyHolidayIDs := make(map[string]uint, len(companyHolidays))
+		for _, companyHoliday := range companyHolidays {
+			extra = append(extra, calendar.Holiday{Date: companyHoliday.Date, Name: companyHoliday.Name})
+			companyHolidayIDs[holidayKey(companyHoliday.Date, companyHoliday.Name)] = companyHoliday.ID
+		}
+
+		response := dto.MapHolidaysToResponse(calendar.New(extra).Holidays(from, to))
+		for i := range response {
+			if response[i].Substitute {
+				continue
+			}
+			key := holidayKey(response[i].Date, response[i].Name)
+			if id, ok := companyHolidayIDs[key]; ok {
+				response[i].ID = id
+				response[i].CompanyID = uint(companyID)
+			} else {
+				response[i].ID = ids[key]
+			}
+		}
+		return c.JSON(response)
+	}
+}
+
+// 공휴일 추가 (선거일, 임시공휴일)
+func CreatePublicHolidayHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var request dto.HolidayRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		date, _ := time.Parse("2006-01-02", request.Date)
+		holiday := models.PublicHoliday{Date: date, Name: request.Name}
+		if err := db.DB.Where("date = ?", date).FirstOrCreate(&holiday).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if holiday.Name != request.Name {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Holiday already exists on this date"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(dto.MapPublicHolidayToResponse(holiday))
+	}
+}
+
+func DeletePublicHolidayHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		holidayID, err := strconv.ParseUint(c.Params("holidayID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid holiday ID"})
+		}
+
+		result := db.DB.Delete(&models.PublicHoliday{}, holidayID)
+		if result.Error != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": result.Error.Error()})
+		}
+		if result.RowsAffected == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Holiday not found"})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// 기간의 근무일수 계산. start, end (2006-01-02), half_first, half_last, company_id
+func GetWorkingDaysHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start, err := time.Parse("2006-01-02", c.Query("start"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid start date"})
+		}
+		end, err := time.Parse("2006-01-02", c.Query("end"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid end date"})
+		}
+		if end.Before(start) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "End date is before start date"})
+		}
+
+		companyID, err := strconv.ParseUint(c.Query("company_id", "0"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		cal, err := calendar.Load(db.DB, uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.WorkingDaysResponse{
+			StartDate:   start,
+			EndDate:     end,
+			WorkingDays: cal.WorkingDays(start, end, c.QueryBool("half_first"), c.QueryBool("half_last")),
+		})
+	}
+}
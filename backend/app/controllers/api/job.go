@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetJobsHandler(jobScheduler *scheduler.Scheduler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		statuses, err := jobScheduler.Jobs()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		response := make([]dto.JobResponse, 0, len(statuses))
+		for _, status := range statuses {
+			response = append(response, dto.MapJobStatusToResponse(status))
+		}
+		return c.JSON(response)
+	}
+}
+
+// 작업 수동 실행. 작업이 끝날 때까지 기다린 뒤 실행 결과를 반환한다.
+func RunJobHandler(jobScheduler *scheduler.Scheduler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		run, err := jobScheduler.RunNow(c.Params("jobName"))
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, scheduler.ErrJobLocked) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil && run == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapJobRunToResponse(run))
+	}
+}
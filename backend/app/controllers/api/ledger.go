@@ -0,0 +1,77 @@
+package api
+
+import (
+	"strconv"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// 멤버의 휴가 원장 조회. given_vacation_id 쿼리로 지급분별 조회
+func GetVacationLedgerHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		query := db.DB.Where("member_id = ?", memberID).Order("id ASC")
+		if givenVacationID := c.Query("given_vacation_id"); givenVacationID != "" {
+			query = query.Where("given_vacation_id = ?", givenVacationID)
+		}
+
+		var entries []models.VacationLedger
+		if err := query.Find(&entries).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		response := make([]dto.VacationLedgerResponse, 0, len(entries))
+		for _, entry := range entries {
+			response = append(response, dto.MapVacationLedgerToResponse(entry))
+		}
+		return c.JSON(response)
+	}
+}
+
+// 지급분 수동 조정. days 가 음수면 차감
+func AdjustGivenVacationHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		givenVacationID, err := strconv.ParseUint(c.Params("givenVacationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid given vacation ID"})
+		}
+
+		var request dto.AdjustVacationRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var givenVacation models.GivenVacation
+		if err := db.DB.Where("member_id = ?", memberID).First(&givenVacation, givenVacationID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Given vacation not found"})
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return ledger.Adjust(tx, &givenVacation, request.Days, request.Memo)
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapGivenVacationToResponse(givenVacation))
+	}
+}
@@ -49,6 +49,7 @@ func CreateCompanyMembersHandler(db *database.Database) fiber.Handler {
 				Password:  string(hashedPassword),
 				HireDate:  memberDTO.HireDate,
 				IsActive:  true, // 자동으로 True 설정
+				Language:  memberDTO.Language,
 			}
 			members = append(members, member)
 		}
@@ -1,44 +1,284 @@
 package api
 
 import (
-	"cywell.com/vacation-promotion/app/models"
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/notify"
+	"cywell.com/vacation-promotion/app/stream"
 	"cywell.com/vacation-promotion/database"
 	"github.com/gofiber/fiber/v2"
 )
 
+// 촉진 절차에서 확인하는 알림. 일반 확인으로 처리하면 절차가 진행되지 않으므로 막는다
+var workflowNotificationTypes = map[uint]string{
+	enums.NotificationTypeVacationFirstPromotion:  "사용 계획 제출",
+	enums.NotificationTypeVacationSecondPromotion: "사용 시기 지정 통보 확인",
+	enums.NotificationTypeVacationDenyWork:        "노무수령 거부 통지 확인",
+}
+
+// 멤버 수신함. page, size(최대 100), unread, type(쉼표 구분) 쿼리. 최근 알림부터. 로그인한 본인만 볼 수 있다
 func GetAllNotificationsHandler(db *database.Database) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		memberID := c.Params("memberID")
-		var notifications []models.Notification
-		if err := db.DB.Where("member_id = ?", memberID).Find(&notifications).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
-		}
-		return c.JSON(notifications)
+		return listNotifications(c, db, notify.InboxFilter{UnreadOnly: c.QueryBool("unread")})
 	}
 }
 
+// 아직 확인하지 않은 알림. page, size, type 쿼리
 func GetNewNotificationsHandler(db *database.Database) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		memberID := c.Params("memberID")
-		var notifications []models.Notification
-		if err := db.DB.Where("member_id = ? AND is_approve = ?", memberID, false).Find(&notifications).Error; err != nil {
+		return listNotifications(c, db, notify.InboxFilter{UnacknowledgedOnly: true})
+	}
+}
+
+func listNotifications(c *fiber.Ctx, db *database.Database, filter notify.InboxFilter) error {
+	memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+	}
+	if !isCurrentMember(c, uint(memberID)) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 확인할 수 있습니다"})
+	}
+	if typeQ := c.Query("type"); typeQ != "" {
+		for _, typeStr := range strings.Split(typeQ, ",") {
+			typeID, err := strconv.ParseUint(strings.TrimSpace(typeStr), 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification type"})
+			}
+			filter.TypeIDs = append(filter.TypeIDs, uint(typeID))
+		}
+	}
+
+	page := notify.Page{Number: c.QueryInt("page", 1), Size: c.QueryInt("size", notify.DefaultPageSize)}
+	inbox, err := notify.List(db.DB, uint(memberID), filter, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(dto.MapInboxToResponse(inbox))
+}
+
+// 새 알림과 휴가 계획 상태 변경을 SSE 로 보낸다. 로그인한 본인만 구독할 수 있다.
+// 다시 접속할 때 Last-Event-ID 헤더(또는 lastEventId 쿼리)가 있으면 놓친 이벤트부터 보내고,
+// 이어 보낼 수 없으면 resync 이벤트를 먼저 보낸다
+func StreamNotificationsHandler(hub *stream.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		if !isCurrentMember(c, uint(memberID)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 구독할 수 있습니다"})
+		}
+		lastEventID := c.Get("Last-Event-ID", c.Query("lastEventId"))
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			sub, replay, resync := hub.Subscribe(uint(memberID), lastEventID)
+			defer sub.Close()
+
+			fmt.Fprintf(w, "retry: %d\n\n", 3000)
+			if resync {
+				hub.ResyncEvent().WriteTo(w)
+			}
+			for _, event := range replay {
+				event.WriteTo(w)
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			heartbeat := time.NewTicker(stream.HeartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case event, ok := <-sub.Events():
+					if !ok {
+						return
+					}
+					event.WriteTo(w)
+				case <-heartbeat.C:
+					w.WriteString(": ping\n\n")
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+		return nil
+	}
+}
+
+func GetNotificationHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, notificationID, err := notificationParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !isCurrentMember(c, memberID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 확인할 수 있습니다"})
+		}
+
+		recipient, err := notify.Get(db.DB, memberID, notificationID)
+		if err != nil {
+			return notificationErrorResponse(c, err)
+		}
+		return c.JSON(dto.MapNotificationToResponse(recipient))
+	}
+}
+
+// 알림 읽음 처리. 처음 읽은 시각을 유지한다
+func ReadNotificationHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, notificationID, err := notificationParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !isCurrentMember(c, memberID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 확인할 수 있습니다"})
+		}
+
+		recipient, err := notify.MarkRead(db.DB, memberID, notificationID, time.Now())
+		if err != nil {
+			return notificationErrorResponse(c, err)
+		}
+		return c.JSON(dto.MapNotificationToResponse(recipient))
+	}
+}
+
+func ReadAllNotificationsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		if !isCurrentMember(c, uint(memberID)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 확인할 수 있습니다"})
+		}
+
+		updated, err := notify.MarkAllRead(db.DB, uint(memberID), time.Now())
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
-		return c.JSON(notifications)
+		return c.JSON(dto.ReadAllNotificationsResponse{Updated: updated})
 	}
 }
 
+// 알림 확인 처리. 촉진 절차의 알림은 각 절차의 API 로 확인해야 한다
 func ApproveNotificationHandler(db *database.Database) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		notificationID := c.Params("notificationID")
-		var notification models.Notification
-		if err := db.DB.First(&notification, notificationID).Error; err != nil {
+		memberID, notificationID, err := notificationParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !isCurrentMember(c, memberID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인의 알림만 확인할 수 있습니다"})
+		}
+
+		recipient, err := notify.Get(db.DB, memberID, notificationID)
+		if err != nil {
+			return notificationErrorResponse(c, err)
+		}
+		if workflow, ok := workflowNotificationTypes[recipient.Notification.NotificationTypeID]; ok {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": workflow + " API 로 확인하는 알림입니다"})
+		}
+
+		recipient, err = notify.Acknowledge(db.DB, memberID, notificationID, time.Now())
+		if err != nil {
+			return notificationErrorResponse(c, err)
+		}
+		return c.JSON(dto.MapNotificationToResponse(recipient))
+	}
+}
+
+// 회사 멤버에게 보낸 이메일 발송 기록. status, notificationID, memberID, page, size 쿼리
+func GetNotificationDeliveriesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		filter := notify.DeliveryFilter{Status: c.Query("status")}
+		switch filter.Status {
+		case "", notify.DeliveryPending, notify.DeliverySent, notify.DeliveryFailed:
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid delivery status"})
+		}
+		if notificationQ := c.Query("notificationID"); notificationQ != "" {
+			notificationID, err := strconv.ParseUint(notificationQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID"})
+			}
+			filter.NotificationID = uint(notificationID)
+		}
+		if memberQ := c.Query("memberID"); memberQ != "" {
+			memberID, err := strconv.ParseUint(memberQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+			}
+			filter.MemberID = uint(memberID)
+		}
+
+		page := notify.Page{Number: c.QueryInt("page", 1), Size: c.QueryInt("size", notify.DefaultPageSize)}
+		deliveries, err := notify.ListDeliveries(db.DB, uint(companyID), filter, page)
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
-		//notification.IsApprove = true
-		if err := db.DB.Save(&notification).Error; err != nil {
+		return c.JSON(dto.MapDeliveryPageToResponse(deliveries))
+	}
+}
+
+// 이메일 다시 보내기. 다음 발송 작업에서 재시도 횟수를 새로 세어 보낸다
+func RetryNotificationDeliveryHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		deliveryID, err := strconv.ParseUint(c.Params("deliveryID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid delivery ID"})
+		}
+
+		delivery, err := notify.RetryDelivery(db.DB, uint(companyID), uint(deliveryID), time.Now())
+		if errors.Is(err, notify.ErrDeliveryNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, notify.ErrDeliverySent) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
-		return c.JSON(notification)
+		return c.JSON(dto.MapNotificationDeliveryToResponse(delivery))
+	}
+}
+
+func notificationParams(c *fiber.Ctx) (uint, uint, error) {
+	memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid member ID")
+	}
+	notificationID, err := strconv.ParseUint(c.Params("notificationID"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid notification ID")
+	}
+	return uint(memberID), uint(notificationID), nil
+}
+
+func notificationErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, notify.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 }
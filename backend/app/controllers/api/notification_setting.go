@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// 멤버 알림 수신 설정. 모든 알림 종류를 돌려주며 설정하지 않은 종류는 기본값(앱, 이메일 즉시 발송)이다
+func GetNotificationSettingsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		var member models.Member
+		if err := db.DB.First(&member, memberID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+		}
+
+		preferences, err := notify.LoadPreferences(db.DB, member.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapNotificationSettingsToResponse(member.ID, preferences))
+	}
+}
+
+// 멤버 알림 수신 설정 변경. 법정 촉진 알림의 수신 거부는 저장하지만 보낼 때는 무시한다
+func UpdateNotificationSettingsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+
+		var request dto.NotificationSettingsRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var member models.Member
+		if err := db.DB.First(&member, memberID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+		}
+
+		typeIDs := make([]uint, 0, len(request.Preferences))
+		preferences := make([]models.NotificationPreference, 0, len(request.Preferences))
+		seen := make(map[uint]bool, len(request.Preferences))
+		for _, preference := range request.Preferences {
+			if seen[preference.NotificationTypeID] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "알림 종류가 중복되었습니다"})
+			}
+			seen[preference.NotificationTypeID] = true
+			typeIDs = append(typeIDs, preference.NotificationTypeID)
+			preferences = append(preferences, models.NotificationPreference{
+				NotificationTypeID: preference.NotificationTypeID,
+				InApp:              *preference.InApp,
+				Email:              *preference.Email,
+				Webhook:            *preference.Webhook,
+				Digest:             preference.Digest,
+			})
+		}
+		if len(typeIDs) > 0 {
+			var count int64
+			if err := db.DB.Model(&models.NotificationType{}).Where("id IN ?", typeIDs).Count(&count).Error; err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+			if int(count) != len(typeIDs) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification type"})
+			}
+		}
+
+		setting := models.NotificationSetting{
+			QuietHoursStart: request.QuietHoursStart,
+			QuietHoursEnd:   request.QuietHoursEnd,
+		}
+		var saved notify.Preferences
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := notify.SavePreferences(tx, member.ID, setting, preferences); err != nil {
+				return err
+			}
+			saved, err = notify.LoadPreferences(tx, member.ID)
+			return err
+		})
+		if errors.Is(err, notify.ErrInvalidQuietHours) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapNotificationSettingsToResponse(member.ID, saved))
+	}
+}
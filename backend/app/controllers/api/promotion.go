@@ -0,0 +1,482 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/auth"
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/promotion"
+	"cywell.com/vacation-promotion/app/stream"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 회사 전체 지급분의 촉진 상태를 기한에 맞춰 진행
+func AdvanceCompanyPromotionsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		histories, err := promotion.NewEngine(db, time.Now).AdvanceCompany(uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapPromotionHistoriesToResponse(histories))
+	}
+}
+
+// 지급분의 촉진 기한과 상태 변경 이력 조회
+func GetGrantPromotionHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		givenVacationID, err := strconv.ParseUint(c.Params("givenVacationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid given vacation ID"})
+		}
+
+		var givenVacation models.GivenVacation
+		if err := db.DB.Preload("Member").Where("member_id = ?", memberID).First(&givenVacation, givenVacationID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Given vacation not found"})
+		}
+		setting, err := promotion.LoadSetting(db.DB, givenVacation.Member.CompanyID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		firstNotifiedAt, err := promotion.FirstNotifiedAt(db.DB, givenVacation.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		histories, err := promotion.Histories(db.DB, givenVacation.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.GrantPromotionResponse{
+			GivenVacation: dto.MapGivenVacationToResponse(givenVacation),
+			Applicable:    promotion.Applicable(givenVacation),
+			Deadlines:     dto.MapPromotionDeadlinesToResponse(promotion.DeadlinesOf(givenVacation, firstNotifiedAt, setting)),
+			Histories:     dto.MapPromotionHistoriesToResponse(histories),
+		})
+	}
+}
+
+// 촉진 현황. 재직 멤버의 사용 기간 중인 지급분별 촉진 상태, 미사용 일수, 다음 기한과 지연 일수
+// state(쉼표 구분), organizeID(하위 조직 포함), groupID, needs_action, track 쿼리로 필터
+// 1년 미만 근로자의 월 단위 지급분은 기한이 달라 tracks 에 따로 집계
+func GetPromotionsHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		today := time.Now()
+
+		query := db.DB.Model(&models.GivenVacation{}).
+			Joins("JOIN members ON members.id = given_vacations.member_id").
+			Where("members.company_id = ? AND members.is_active = ?", companyID, true).
+			Where("given_vacations.is_expired = ? AND given_vacations.expire_date > ?", false, today).
+			Preload("Member").
+			Preload("VacationPromotionState").
+			Order("given_vacations.expire_date ASC, given_vacations.member_id ASC")
+
+		if stateQ := c.Query("state"); stateQ != "" {
+			stateIDs := make([]uint64, 0)
+			for _, stateStr := range strings.Split(stateQ, ",") {
+				stateID, err := strconv.ParseUint(strings.TrimSpace(stateStr), 10, 32)
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid state"})
+				}
+				stateIDs = append(stateIDs, stateID)
+			}
+			query = query.Where("given_vacations.vacation_promotion_state_id IN ?", stateIDs)
+		}
+		if organizeQ := c.Query("organizeID"); organizeQ != "" {
+			organizeID, err := strconv.ParseUint(organizeQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid organize ID"})
+			}
+			organizeIDs, err := organizeSubtree(db, uint(companyID), uint(organizeID))
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+			query = query.Where("members.organize_id IN ?", organizeIDs)
+		}
+		if groupQ := c.Query("groupID"); groupQ != "" {
+			groupID, err := strconv.ParseUint(groupQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid group ID"})
+			}
+			query = query.Where("given_vacations.member_id IN (SELECT member_id FROM group_members WHERE group_id = ?)", groupID)
+		}
+
+		var givenVacations []models.GivenVacation
+		if err := query.Find(&givenVacations).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		givenVacationIDs := make([]uint, 0, len(givenVacations))
+		for _, givenVacation := range givenVacations {
+			givenVacationIDs = append(givenVacationIDs, givenVacation.ID)
+		}
+		notifiedAt, err := promotion.FirstNotifiedAtByGrant(db.DB, givenVacationIDs)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		setting, err := promotion.LoadSetting(db.DB, uint(companyID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		onlyNeedsAction := c.QueryBool("needs_action")
+		track := c.Query("track")
+		response := dto.PromotionDashboardResponse{
+			Enabled:     setting.Enabled,
+			StateCounts: make(map[uint]int),
+			Tracks:      make([]dto.PromotionTrackResponse, 0, 3),
+			Items:       make([]dto.PromotionStatusResponse, 0, len(givenVacations)),
+		}
+		trackIndex := make(map[string]int)
+		for _, name := range []string{promotion.TrackAnnual, promotion.TrackFirstYearNine, promotion.TrackFirstYearTwo} {
+			if track != "" && track != name {
+				continue
+			}
+			trackIndex[name] = len(response.Tracks)
+			response.Tracks = append(response.Tracks, dto.PromotionTrackResponse{Track: name, StateCounts: make(map[uint]int)})
+		}
+		if len(response.Tracks) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid track"})
+		}
+
+		for _, givenVacation := range givenVacations {
+			if !promotion.Applicable(givenVacation) {
+				continue
+			}
+			status := promotion.StatusOf(givenVacation, notifiedAt[givenVacation.ID], today, setting)
+			if onlyNeedsAction && !status.NeedsAction {
+				continue
+			}
+			index, ok := trackIndex[status.Deadlines.Track]
+			if !ok {
+				continue
+			}
+			summary := &response.Tracks[index]
+
+			response.Total++
+			summary.Total++
+			response.StateCounts[givenVacation.VacationPromotionStateID]++
+			summary.StateCounts[givenVacation.VacationPromotionStateID]++
+			if status.NeedsAction {
+				response.NeedsAction++
+				summary.NeedsAction++
+			}
+			if status.DaysOverdue > 0 {
+				response.Overdue++
+				summary.Overdue++
+			}
+			response.Items = append(response.Items, dto.MapPromotionStatusToResponse(givenVacation, status))
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// organizeSubtree 는 조직과 모든 하위 조직의 ID 를 반환한다.
+func organizeSubtree(db *database.Database, companyID, organizeID uint) ([]uint, error) {
+	var organizes []models.Organize
+	if err := db.DB.Where("company_id = ?", companyID).Find(&organizes).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	for _, organize := range organizes {
+		if organize.ParentID != nil {
+			children[*organize.ParentID] = append(children[*organize.ParentID], organize.ID)
+		}
+	}
+
+	subtree := []uint{organizeID}
+	for i := 0; i < len(subtree); i++ {
+		subtree = append(subtree, children[subtree[i]]...)
+	}
+	return subtree, nil
+}
+
+// 1차 촉진 통지 미리보기. organizeID 쿼리로 조직(하위 조직 포함) 한정
+func PreviewFirstNoticesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var filter promotion.NoticeFilter
+		if organizeQ := c.Query("organizeID"); organizeQ != "" {
+			organizeID, err := strconv.ParseUint(organizeQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid organize ID"})
+			}
+			if filter.OrganizeIDs, err = organizeSubtree(db, uint(companyID), uint(organizeID)); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		notices, err := promotion.NewEngine(db, time.Now).FirstNoticeCandidates(uint(companyID), filter)
+		if err != nil {
+			if errors.Is(err, promotion.ErrPromotionDisabled) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapPromotionNoticesToResponse(notices))
+	}
+}
+
+// 1차 촉진 통지 일괄 발송
+func SendFirstNoticesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.SendPromotionNoticesRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&request); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		filter := promotion.NoticeFilter{GivenVacationIDs: request.GivenVacationIDs}
+		if request.OrganizeID != nil {
+			if filter.OrganizeIDs, err = organizeSubtree(db, uint(companyID), *request.OrganizeID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		notices, err := promotion.NewEngine(db, time.Now).SendFirstNotices(uint(companyID), filter, actorID(c))
+		if err != nil {
+			if errors.Is(err, promotion.ErrPromotionDisabled) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(dto.MapPromotionNoticesToResponse(notices))
+	}
+}
+
+// 요청한 멤버 ID. 촉진 이력의 처리자로 남긴다
+func actorID(c *fiber.Ctx) *uint {
+	memberID, ok := auth.CurrentMemberID(c)
+	if !ok {
+		return nil
+	}
+	return &memberID
+}
+
+// isCurrentMember 는 로그인한 멤버가 memberID 본인인지 확인한다. 통지 확인처럼 본인만 할 수 있는 처리에 쓴다.
+func isCurrentMember(c *fiber.Ctx, memberID uint) bool {
+	current, ok := auth.CurrentMemberID(c)
+	return ok && current == memberID
+}
+
+// 1차 촉진 통지에 대한 멤버의 촉진 휴가 사용 계획 제출. 통지를 받은 본인만 제출할 수 있다
+func SubmitPromotionPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		notificationID, err := strconv.ParseUint(c.Params("notificationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID"})
+		}
+		if !isCurrentMember(c, uint(memberID)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인에게 온 통지에만 사용 계획을 제출할 수 있습니다"})
+		}
+
+		var request dto.PromotionPlanRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		vacations := make([]promotion.PlannedVacation, 0, len(request.Vacations))
+		for _, vacation := range request.Vacations {
+			vacations = append(vacations, promotion.PlannedVacation{
+				StartDate: vacation.StartDate,
+				EndDate:   vacation.EndDate,
+				HalfFirst: vacation.HalfFirst,
+				HalfLast:  vacation.HalfLast,
+			})
+		}
+
+		result, err := promotion.NewEngine(db, time.Now).SubmitUsePlan(uint(memberID), uint(notificationID), vacations, request.ApproverOrder)
+		if err != nil {
+			switch {
+			case errors.Is(err, promotion.ErrNoticeNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrNotAwaitingResponse), errors.Is(err, promotion.ErrInvalidTransition):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrOutsideUsePeriod), errors.Is(err, promotion.ErrApproverRequired):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			return balanceErrorResponse(c, err)
+		}
+		publishPlanEvent(db, hub, result.Plan, stream.PlanApplied)
+
+		planResponse := dto.MapVacationPlanToResponse(result.Plan)
+		for _, vacation := range result.Plan.ApplyVacations {
+			planResponse.Vacations = append(planResponse.Vacations, dto.MapApplyVacationToResponse(vacation))
+		}
+
+		givenVacations := make([]dto.GivenVacationResponse, 0, len(result.GivenVacations))
+		for _, grant := range result.GivenVacations {
+			givenVacations = append(givenVacations, dto.MapGivenVacationToResponse(*grant))
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(dto.PromotionPlanResponse{
+			Plan:           planResponse,
+			GivenVacations: givenVacations,
+			PlannedDays:    result.PlannedDays,
+			UnplannedDays:  result.UnplannedDays,
+			Histories:      dto.MapPromotionHistoriesToResponse(result.Histories),
+		})
+	}
+}
+
+// 2차 촉진 사용 시기 지정 미리보기. 지급분별 제안 날짜. organizeID 쿼리로 조직(하위 조직 포함) 한정
+func PreviewSecondNoticesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var filter promotion.NoticeFilter
+		if organizeQ := c.Query("organizeID"); organizeQ != "" {
+			organizeID, err := strconv.ParseUint(organizeQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid organize ID"})
+			}
+			if filter.OrganizeIDs, err = organizeSubtree(db, uint(companyID), uint(organizeID)); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		designations, err := promotion.NewEngine(db, time.Now).SecondNoticeCandidates(uint(companyID), filter)
+		if err != nil {
+			if errors.Is(err, promotion.ErrPromotionDisabled) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapPromotionDesignationsToResponse(designations))
+	}
+}
+
+// 2차 촉진 사용 시기 지정 통보 일괄 발송. 직접 지정하지 않은 지급분은 제안 날짜로 지정.
+// 보내지 못한 묶음은 응답의 skipped 에 이유를 담고 나머지는 보낸다
+func SendSecondNoticesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var request dto.SendSecondNoticesRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&request); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		filter := promotion.NoticeFilter{GivenVacationIDs: request.GivenVacationIDs}
+		if request.OrganizeID != nil {
+			if filter.OrganizeIDs, err = organizeSubtree(db, uint(companyID), *request.OrganizeID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		designated := make(map[uint][]promotion.PlannedVacation, len(request.Designations))
+		for _, designation := range request.Designations {
+			for _, vacation := range designation.Vacations {
+				designated[designation.GivenVacationID] = append(designated[designation.GivenVacationID], promotion.PlannedVacation{
+					StartDate: vacation.StartDate,
+					EndDate:   vacation.EndDate,
+					HalfFirst: vacation.HalfFirst,
+					HalfLast:  vacation.HalfLast,
+				})
+			}
+			if len(request.GivenVacationIDs) == 0 {
+				filter.GivenVacationIDs = append(filter.GivenVacationIDs, designation.GivenVacationID)
+			}
+		}
+
+		designations, err := promotion.NewEngine(db, time.Now).SendSecondNotices(uint(companyID), filter, designated, actorID(c))
+		if err != nil {
+			if errors.Is(err, promotion.ErrDesignationShort) || errors.Is(err, promotion.ErrOutsideUsePeriod) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			if errors.Is(err, promotion.ErrPromotionDisabled) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return balanceErrorResponse(c, err)
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(dto.MapPromotionDesignationsToResponse(designations))
+	}
+}
+
+// 2차 촉진 사용 시기 지정 통보 확인. 통보를 받은 본인만 확인할 수 있다
+func AcceptSecondNoticeHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid member ID"})
+		}
+		notificationID, err := strconv.ParseUint(c.Params("notificationID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID"})
+		}
+		if !isCurrentMember(c, uint(memberID)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "본인에게 온 통지만 확인할 수 있습니다"})
+		}
+
+		histories, err := promotion.NewEngine(db, time.Now).AcceptSecondNotice(uint(memberID), uint(notificationID))
+		if err != nil {
+			switch {
+			case errors.Is(err, promotion.ErrNoticeNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			case errors.Is(err, promotion.ErrNotAwaitingAcceptance), errors.Is(err, promotion.ErrInvalidTransition):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(dto.MapPromotionHistoriesToResponse(histories))
+	}
+}
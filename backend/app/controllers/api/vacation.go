@@ -3,19 +3,24 @@ package api
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"time"
 
+	"cywell.com/vacation-promotion/app/approval"
 	"cywell.com/vacation-promotion/app/dto"
 	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
 	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/stream"
+	"cywell.com/vacation-promotion/app/webhook"
 	"cywell.com/vacation-promotion/database"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func CreateVacationPlanHandler(db *database.Database) fiber.Handler {
+func CreateVacationPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		memberID, err := strconv.ParseUint(c.Params("memberID"), 10, 32)
 		if err != nil {
@@ -33,6 +38,11 @@ func CreateVacationPlanHandler(db *database.Database) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
+		var member models.Member
+		if err := db.DB.Preload("Company").First(&member, memberID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Member not found"})
+		}
+
 		vacationPlan := models.VacationPlan{
 			MemberID:     uint(memberID),
 			ApplyDate:    time.Now(),
@@ -55,6 +65,11 @@ func CreateVacationPlanHandler(db *database.Database) fiber.Handler {
 					return err
 				}
 			}
+			if len(request.ApproverOrder) > 0 {
+				if err := approval.NotifyApprover(tx, vacationPlan.ID, uint(request.ApproverOrder[0]), member.Name); err != nil {
+					return err
+				}
+			}
 
 			for _, vacation := range request.Vacations {
 				applyVacation := models.ApplyVacation{
@@ -71,14 +86,19 @@ func CreateVacationPlanHandler(db *database.Database) fiber.Handler {
 				if err := tx.Create(&applyVacation).Error; err != nil {
 					return err
 				}
+				if err := ledger.ReserveWithinBalance(tx, applyVacation, member.Company.AdvanceVacationDays, "휴가 신청"); err != nil {
+					return err
+				}
+				vacationPlan.ApplyVacations = append(vacationPlan.ApplyVacations, applyVacation)
 			}
 
-			return nil
+			return webhook.Emit(tx, member.CompanyID, webhook.EventPlanCreated, webhook.PlanDataOf(vacationPlan))
 		})
 
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			return balanceErrorResponse(c, err)
 		}
+		publishPlanEvent(db, hub, vacationPlan, stream.PlanApplied)
 
 		vacationPlanResponse := dto.VacationPlanResponse{
 			ID:        vacationPlan.ID,
@@ -294,7 +314,7 @@ func GetVacationPlansByPeriodHandler(db *database.Database) fiber.Handler {
 	}
 }
 
-func ApproveVacationPlanHandler(db *database.Database) fiber.Handler {
+func ApproveVacationPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		//ID 이상 검증
@@ -331,32 +351,56 @@ func ApproveVacationPlanHandler(db *database.Database) fiber.Handler {
 		plan.ApproveStage = uint(input.ApprovalStage)
 		plan.RejectState = false
 
-		if err := db.DB.Save(&plan).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 계획을 승인할 수 없습니다"})
-		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&plan).Error; err != nil {
+				return errors.New("휴가 계획을 승인할 수 없습니다")
+			}
 
-		// 휴가 상태 업데이트
-		for _, vacation := range plan.ApplyVacations {
-			if !vacation.RejectState {
-				vacation.ApproveStage = uint(input.ApprovalStage)
-				if err := db.DB.Save(&vacation).Error; err != nil {
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 상태를 업데이트할 수 없습니다"})
+			// 휴가 상태 업데이트, 최종 승인이면 예약분을 사용으로 전환
+			for _, vacation := range plan.ApplyVacations {
+				if !vacation.RejectState {
+					vacation.ApproveStage = uint(input.ApprovalStage)
+					if err := tx.Save(&vacation).Error; err != nil {
+						return errors.New("휴가 상태를 업데이트할 수 없습니다")
+					}
+					if plan.CompleteState {
+						if err := ledger.Consume(tx, vacation, "최종 승인"); err != nil {
+							return err
+						}
+					}
 				}
 			}
-		}
 
-		//approve_order의 descisionDate 업데이트
-		if err := db.DB.Model(&models.ApproverOrder{}).
-			Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
-			Update("decision_date", time.Now()).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "승인 날짜를 업데이트할 수 없습니다"})
+			//approve_order의 descisionDate 업데이트
+			if err := tx.Model(&models.ApproverOrder{}).
+				Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
+				Update("decision_date", time.Now()).Error; err != nil {
+				return errors.New("승인 날짜를 업데이트할 수 없습니다")
+			}
+
+			// 다음 결재자에게 결재 차례 알림
+			if !plan.CompleteState && nextApproverOrder.ID != 0 {
+				var applicant models.Member
+				if err := tx.First(&applicant, plan.MemberID).Error; err != nil {
+					return err
+				}
+				if err := approval.NotifyApprover(tx, plan.ID, nextApproverOrder.MemberID, applicant.Name); err != nil {
+					return err
+				}
+			}
+			return webhook.EmitMember(tx, plan.MemberID, webhook.EventPlanApproved, webhook.PlanDataOf(plan))
+		})
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
+		publishPlanEvent(db, hub, plan, stream.PlanApproved)
 
 		return c.JSON(plan)
 	}
 }
 
-func CancelApproveVacationPlanHandler(db *database.Database) fiber.Handler {
+func CancelApproveVacationPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		//ID 이상 검증
@@ -391,6 +435,7 @@ func CancelApproveVacationPlanHandler(db *database.Database) fiber.Handler {
 			return errors.New("승인 권한이 없습니다")
 		}
 
+		wasComplete := plan.CompleteState
 		var nextApproverOrder models.ApproverOrder
 		if err := db.DB.Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage+1).First(&nextApproverOrder).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -400,31 +445,45 @@ func CancelApproveVacationPlanHandler(db *database.Database) fiber.Handler {
 
 		// 휴가 계획 상태 업데이트
 		plan.ApproveStage = uint(input.ApprovalStage - 1)
-		if err := db.DB.Save(&plan).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 계획을 승인할 수 없습니다"})
-		}
 
-		// 휴가 상태 업데이트
-		for _, vacation := range plan.ApplyVacations {
-			if !vacation.RejectState {
-				vacation.ApproveStage = uint(input.ApprovalStage) - 1
-				if err := db.DB.Save(&vacation).Error; err != nil {
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 상태를 업데이트할 수 없습니다"})
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&plan).Error; err != nil {
+				return errors.New("휴가 계획을 승인할 수 없습니다")
+			}
+
+			// 휴가 상태 업데이트, 최종 승인 취소면 사용분을 예약으로 되돌림
+			for _, vacation := range plan.ApplyVacations {
+				if !vacation.RejectState {
+					vacation.ApproveStage = uint(input.ApprovalStage) - 1
+					if err := tx.Save(&vacation).Error; err != nil {
+						return errors.New("휴가 상태를 업데이트할 수 없습니다")
+					}
+					if wasComplete && !plan.CompleteState {
+						if err := ledger.Unconsume(tx, vacation, "최종 승인 취소"); err != nil {
+							return err
+						}
+					}
 				}
 			}
-		}
 
-		if err := db.DB.Model(&models.ApproverOrder{}).
-			Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
-			Update("decision_date", nil).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "승인 날짜를 업데이트할 수 없습니다"})
+			if err := tx.Model(&models.ApproverOrder{}).
+				Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
+				Update("decision_date", nil).Error; err != nil {
+				return errors.New("승인 날짜를 업데이트할 수 없습니다")
+			}
+			return nil
+		})
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
+		publishPlanEvent(db, hub, plan, stream.PlanApprovalCanceled)
 
 		return c.JSON(plan)
 	}
 }
 
-func RejectVacationPlanHandler(db *database.Database) fiber.Handler {
+func RejectVacationPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		//ID 이상
@@ -451,31 +510,43 @@ func RejectVacationPlanHandler(db *database.Database) fiber.Handler {
 		// 휴가 계획 상태 업데이트
 		plan.RejectState = true
 		plan.ApproveStage = uint(input.ApprovalStage)
-		if err := db.DB.Save(&plan).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 계획을 거절할 수 없습니다"})
-		}
 
-		// 휴가 상태 업데이트
-		for _, vacation := range plan.ApplyVacations {
-			// vacation.RejectState = true
-			vacation.ApproveStage = uint(input.ApprovalStage)
-			if err := db.DB.Save(&vacation).Error; err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 상태를 업데이트할 수 없습니다"})
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&plan).Error; err != nil {
+				return errors.New("휴가 계획을 거절할 수 없습니다")
+			}
+
+			// 휴가 상태 업데이트, 예약분 해제. 최종 승인된 계획이면 사용분도 되돌린다
+			for _, vacation := range plan.ApplyVacations {
+				// vacation.RejectState = true
+				vacation.ApproveStage = uint(input.ApprovalStage)
+				if err := tx.Save(&vacation).Error; err != nil {
+					return errors.New("휴가 상태를 업데이트할 수 없습니다")
+				}
+				if err := ledger.Cancel(tx, vacation, "휴가 계획 반려"); err != nil {
+					return err
+				}
 			}
-		}
 
-		//approve_order의 descisionDate 업데이트
-		if err := db.DB.Model(&models.ApproverOrder{}).
-			Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
-			Update("decision_date", time.Now()).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "승인 날짜를 업데이트할 수 없습니다"})
+			//approve_order의 descisionDate 업데이트
+			if err := tx.Model(&models.ApproverOrder{}).
+				Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
+				Update("decision_date", time.Now()).Error; err != nil {
+				return errors.New("승인 날짜를 업데이트할 수 없습니다")
+			}
+			return webhook.EmitMember(tx, plan.MemberID, webhook.EventPlanRejected, webhook.PlanDataOf(plan))
+		})
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
+		publishPlanEvent(db, hub, plan, stream.PlanRejected)
 
 		return c.JSON(plan)
 	}
 }
 
-func CancelRejectVacationPlanHandler(db *database.Database) fiber.Handler {
+func CancelRejectVacationPlanHandler(db *database.Database, hub *stream.Hub) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		//ID 이상
@@ -502,24 +573,48 @@ func CancelRejectVacationPlanHandler(db *database.Database) fiber.Handler {
 		// 휴가 계획 상태 업데이트
 		plan.RejectState = false
 		plan.ApproveStage = uint(input.ApprovalStage) - 1
-		if err := db.DB.Save(&plan).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 계획을 거절할 수 없습니다"})
-		}
 
-		// 휴가 상태 업데이트
-		for _, vacation := range plan.ApplyVacations {
-			// vacation.RejectState = false
-			vacation.ApproveStage = uint(input.ApprovalStage) - 1
-			if err := db.DB.Save(&vacation).Error; err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "휴가 상태를 업데이트할 수 없습니다"})
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&plan).Error; err != nil {
+				return errors.New("휴가 계획을 거절할 수 없습니다")
+			}
+			advanceDays, err := advanceDaysOf(tx, plan.MemberID)
+			if err != nil {
+				return err
 			}
-		}
 
-		if err := db.DB.Model(&models.ApproverOrder{}).
-			Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
-			Update("decision_date", nil).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "승인 날짜를 업데이트할 수 없습니다"})
+			// 휴가 상태 업데이트, 개별 거절되지 않은 휴가는 잔여일수 안에서 다시 예약
+			for _, vacation := range plan.ApplyVacations {
+				// vacation.RejectState = false
+				vacation.ApproveStage = uint(input.ApprovalStage) - 1
+				if err := tx.Save(&vacation).Error; err != nil {
+					return errors.New("휴가 상태를 업데이트할 수 없습니다")
+				}
+				if !vacation.RejectState {
+					if err := ledger.ReserveWithinBalance(tx, vacation, advanceDays, "휴가 계획 반려 취소"); err != nil {
+						return err
+					}
+					// 최종 승인 뒤 반려했던 계획은 사용분으로 되돌린다
+					if plan.CompleteState {
+						if err := ledger.Consume(tx, vacation, "휴가 계획 반려 취소"); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			if err := tx.Model(&models.ApproverOrder{}).
+				Where("vacation_plan_id = ? AND `order` = ?", plan.ID, input.ApprovalStage).
+				Update("decision_date", nil).Error; err != nil {
+				return errors.New("승인 날짜를 업데이트할 수 없습니다")
+			}
+			return nil
+		})
+
+		if err != nil {
+			return balanceErrorResponse(c, err)
 		}
+		publishPlanEvent(db, hub, plan, stream.PlanRejectionCanceled)
 
 		return c.JSON(plan)
 	}
@@ -590,7 +685,7 @@ func UpdateVacationHandler(db *database.Database) fiber.Handler {
 		}
 
 		var vacation models.ApplyVacation
-		if err := db.DB.First(&vacation, vacationID).Error; err != nil {
+		if err := db.DB.Preload("VacationPlan").Preload("Member.Company").First(&vacation, vacationID).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 
@@ -603,8 +698,29 @@ func UpdateVacationHandler(db *database.Database) fiber.Handler {
 		vacation.HalfFirst = editVacationRequest.HalfFirst
 		vacation.HalfLast = editVacationRequest.HalfLast
 
-		if err := db.DB.Save(&vacation).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		// 기존 차감분을 되돌리고 변경된 기간으로 다시 예약
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := ledger.Cancel(tx, vacation, "휴가 수정 전 원복"); err != nil {
+				return err
+			}
+			if err := tx.Omit("VacationPlan", "Member").Save(&vacation).Error; err != nil {
+				return err
+			}
+			// 반려된 계획이나 개별 반려된 휴가는 다시 차감하지 않는다
+			if vacation.RejectState || vacation.VacationPlan.RejectState {
+				return nil
+			}
+			if err := ledger.ReserveWithinBalance(tx, vacation, vacation.Member.Company.AdvanceVacationDays, "휴가 수정"); err != nil {
+				return err
+			}
+			if vacation.VacationPlan.CompleteState {
+				return ledger.Consume(tx, vacation, "휴가 수정")
+			}
+			return nil
+		})
+
+		if err != nil {
+			return balanceErrorResponse(c, err)
 		}
 
 		vacationResponse := dto.MapApplyVacationToResponse(vacation)
@@ -623,7 +739,19 @@ func DeleteVacationPlanHandler(db *database.Database) fiber.Handler {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		if err := tx.First(&plan, planID).Error; err != nil {
+		if err := tx.Preload("ApplyVacations").First(&plan, planID).Error; err != nil {
+			tx.Rollback()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		for _, vacation := range plan.ApplyVacations {
+			if err := ledger.Cancel(tx, vacation, "휴가 계획 삭제"); err != nil {
+				tx.Rollback()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		if err := webhook.EmitMember(tx, plan.MemberID, webhook.EventPlanCancelled, webhook.PlanDataOf(plan)); err != nil {
 			tx.Rollback()
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
@@ -659,7 +787,13 @@ func DeleteVacationHandler(db *database.Database) fiber.Handler {
 		if err := db.DB.First(&vacation, vacationId).Error; err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
-		if err := db.DB.Delete(&vacation).Error; err != nil {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := ledger.Cancel(tx, vacation, "휴가 삭제"); err != nil {
+				return err
+			}
+			return tx.Delete(&vacation).Error
+		})
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(fiber.Map{"message": "Vacation deleted successfully"})
@@ -694,7 +828,13 @@ func RejectVacationHandler(db *database.Database) fiber.Handler {
 		//vacationPlan의 같은 승인자인지도 검사해야함
 
 		vacation.RejectState = true
-		if err := db.DB.Save(&vacation).Error; err != nil {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&vacation).Error; err != nil {
+				return err
+			}
+			return ledger.Cancel(tx, vacation, "휴가 반려")
+		})
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 
@@ -721,8 +861,6 @@ func CancelRejectVacationHandler(db *database.Database) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "이미 거절된 휴가입니다"})
 		}
 
-		//TODO: plan의 거절여부 확인
-
 		//승인 단계 확인
 		if vacation.ApproveStage != uint(input.ApprovalStage)-1 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "잘못된 승인 단계입니다"})
@@ -731,8 +869,32 @@ func CancelRejectVacationHandler(db *database.Database) fiber.Handler {
 		//vacationPlan의 같은 승인자인지도 검사해야함
 
 		vacation.RejectState = false
-		if err := db.DB.Save(&vacation).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&vacation).Error; err != nil {
+				return err
+			}
+			var plan models.VacationPlan
+			if err := tx.First(&plan, vacation.VacationPlanID).Error; err != nil {
+				return err
+			}
+			// 계획이 반려된 동안에는 예약하지 않는다. 계획 반려를 취소할 때 함께 예약된다
+			if plan.RejectState {
+				return nil
+			}
+			advanceDays, err := advanceDaysOf(tx, vacation.MemberID)
+			if err != nil {
+				return err
+			}
+			if err := ledger.ReserveWithinBalance(tx, vacation, advanceDays, "휴가 반려 취소"); err != nil {
+				return err
+			}
+			if plan.CompleteState {
+				return ledger.Consume(tx, vacation, "휴가 반려 취소")
+			}
+			return nil
+		})
+		if err != nil {
+			return balanceErrorResponse(c, err)
 		}
 
 		vacationResponse := dto.MapApplyVacationToResponse(vacation)
@@ -740,15 +902,52 @@ func CancelRejectVacationHandler(db *database.Database) fiber.Handler {
 	}
 }
 
-func GetPromotionsHandler(db *database.Database) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		companyID := c.Params("companyID")
-		var promotions []models.GivenVacation
-		if err := db.DB.Where("company_id = ? AND vacation_promotion_state_id = ?", companyID, 2).Find(&promotions).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
-		}
-		return c.JSON(promotions)
+// publishPlanEvent 는 커밋된 휴가 계획 상태 변경을 신청자와 결재자 스트림으로 보낸다.
+// 스트림은 부가 기능이므로 실패해도 요청은 성공으로 처리한다.
+func publishPlanEvent(db *database.Database, hub *stream.Hub, plan models.VacationPlan, state string) {
+	var memberIDs []uint
+	if err := db.Model(&models.ApproverOrder{}).Where("vacation_plan_id = ?", plan.ID).Pluck("member_id", &memberIDs).Error; err != nil {
+		log.Println("plan event: ", err)
+	}
+	event := dto.VacationPlanEventResponse{
+		VacationPlanID: plan.ID,
+		MemberID:       plan.MemberID,
+		State:          state,
+		ApproveStage:   plan.ApproveStage,
+		CompleteState:  plan.CompleteState,
+		RejectState:    plan.RejectState,
+		ChangedAt:      time.Now(),
+	}
+	if err := hub.Publish(stream.EventPlan, event, append([]uint{plan.MemberID}, memberIDs...)...); err != nil {
+		log.Println("plan event: ", err)
+	}
+}
+
+// advanceDaysOf 는 멤버 회사의 미리 당겨쓰기 허용 일수이다.
+func advanceDaysOf(tx *gorm.DB, memberID uint) (float32, error) {
+	var member models.Member
+	if err := tx.Preload("Company").First(&member, memberID).Error; err != nil {
+		return 0, err
+	}
+	return member.Company.AdvanceVacationDays, nil
+}
+
+// 잔여 휴가 관련 오류를 응답으로 변환
+func balanceErrorResponse(c *fiber.Ctx, err error) error {
+	var insufficient *ledger.InsufficientBalanceError
+	if errors.As(err, &insufficient) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":          insufficient.Error(),
+			"code":           "insufficient_balance",
+			"requested_days": insufficient.RequestedDays,
+			"available_days": insufficient.AvailableDays,
+			"advance_days":   insufficient.AdvanceDays,
+		})
+	}
+	if errors.Is(err, ledger.ErrNoGrant) || errors.Is(err, ledger.ErrZeroCost) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 }
 
 func parseParams(c *fiber.Ctx) (uint64, uint64, uint64, uint64, int, int, error) {
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"cywell.com/vacation-promotion/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func GetWebhooksHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		var endpoints []models.WebhookEndpoint
+		if err := db.DB.Where("company_id = ?", companyID).Order("id ASC").Find(&endpoints).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		response := make([]dto.WebhookResponse, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			response = append(response, dto.MapWebhookToResponse(endpoint, false))
+		}
+		return c.JSON(response)
+	}
+}
+
+// 웹훅 등록. 서명 키는 응답에서 한 번만 보여 준다
+func CreateWebhookHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		request, events, err := parseWebhookRequest(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var company models.Company
+		if err := db.DB.First(&company, companyID).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Company not found"})
+		}
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		endpoint := models.WebhookEndpoint{
+			CompanyID:   company.ID,
+			URL:         request.URL,
+			Secret:      secret,
+			Events:      events,
+			Description: request.Description,
+			Active:      *request.Active,
+		}
+		if err := db.DB.Create(&endpoint).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusCreated).JSON(dto.MapWebhookToResponse(endpoint, true))
+	}
+}
+
+func UpdateWebhookHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, webhookID, err := webhookParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		endpoint, err := findWebhook(db, companyID, webhookID)
+		if err != nil {
+			return webhookErrorResponse(c, err)
+		}
+		request, events, err := parseWebhookRequest(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		endpoint.URL = request.URL
+		endpoint.Events = events
+		endpoint.Description = request.Description
+		endpoint.Active = *request.Active
+		if err := db.DB.Save(&endpoint).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapWebhookToResponse(endpoint, false))
+	}
+}
+
+// 웹훅 삭제. 발송 기록도 함께 지운다
+func DeleteWebhookHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, webhookID, err := webhookParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		endpoint, err := findWebhook(db, companyID, webhookID)
+		if err != nil {
+			return webhookErrorResponse(c, err)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("webhook_endpoint_id = ?", endpoint.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&endpoint).Error
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// 서명 키 새로 발급. 이전 키로 서명한 대기 건도 보낼 때 새 키로 서명한다
+func RotateWebhookSecretHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, webhookID, err := webhookParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		endpoint, err := findWebhook(db, companyID, webhookID)
+		if err != nil {
+			return webhookErrorResponse(c, err)
+		}
+
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		endpoint.Secret = secret
+		if err := db.DB.Model(&endpoint).Update("secret", secret).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapWebhookToResponse(endpoint, true))
+	}
+}
+
+// 주소 확인용 ping 이벤트 발송 예약. 다음 웹훅 발송 작업에서 보낸다
+func PingWebhookHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, webhookID, err := webhookParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		endpoint, err := findWebhook(db, companyID, webhookID)
+		if err != nil {
+			return webhookErrorResponse(c, err)
+		}
+
+		delivery, err := webhook.Ping(db.DB, endpoint)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		delivery.WebhookEndpoint = endpoint
+		return c.Status(fiber.StatusAccepted).JSON(dto.MapWebhookDeliveryToResponse(delivery))
+	}
+}
+
+// 회사 웹훅 발송 기록. status(pending, delivered, dead), webhookID, event, page, size 쿼리. status=dead 가 발송 실패 목록
+func GetWebhookDeliveriesHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+
+		filter := webhook.DeliveryFilter{Status: c.Query("status"), EventType: c.Query("event")}
+		switch filter.Status {
+		case "", webhook.DeliveryPending, webhook.DeliveryDelivered, webhook.DeliveryDead:
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid delivery status"})
+		}
+		if webhookQ := c.Query("webhookID"); webhookQ != "" {
+			webhookID, err := strconv.ParseUint(webhookQ, 10, 32)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook ID"})
+			}
+			filter.EndpointID = uint(webhookID)
+		}
+
+		page := webhook.Page{Number: c.QueryInt("page", 1), Size: c.QueryInt("size", 20)}
+		deliveries, err := webhook.ListDeliveries(db.DB, uint(companyID), filter, page)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(dto.MapWebhookDeliveryPageToResponse(deliveries))
+	}
+}
+
+// 웹훅 다시 보내기. 같은 이벤트 ID 와 본문으로 다음 발송 작업에서 보낸다
+func RedeliverWebhookHandler(db *database.Database) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid company ID"})
+		}
+		deliveryID, err := strconv.ParseUint(c.Params("deliveryID"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid delivery ID"})
+		}
+
+		delivery, err := webhook.Redeliver(db.DB, uint(companyID), uint(deliveryID), time.Now())
+		if err != nil {
+			return webhookErrorResponse(c, err)
+		}
+		return c.JSON(dto.MapWebhookDeliveryToResponse(delivery))
+	}
+}
+
+func parseWebhookRequest(c *fiber.Ctx) (dto.WebhookRequest, string, error) {
+	var request dto.WebhookRequest
+	if err := c.BodyParser(&request); err != nil {
+		return request, "", err
+	}
+	validate := validator.New()
+	if err := validate.Struct(request); err != nil {
+		return request, "", err
+	}
+	if err := webhook.CheckURL(request.URL); err != nil {
+		return request, "", err
+	}
+	events, err := webhook.ParseEvents(request.Events)
+	return request, events, err
+}
+
+func webhookParams(c *fiber.Ctx) (uint, uint, error) {
+	companyID, err := strconv.ParseUint(c.Params("companyID"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid company ID")
+	}
+	webhookID, err := strconv.ParseUint(c.Params("webhookID"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid webhook ID")
+	}
+	return uint(companyID), uint(webhookID), nil
+}
+
+func findWebhook(db *database.Database, companyID, webhookID uint) (models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := db.DB.Where("company_id = ? AND id = ?", companyID, webhookID).First(&endpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return endpoint, webhook.ErrEndpointNotFound
+	}
+	return endpoint, err
+}
+
+func webhookErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, webhook.ErrEndpointNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, webhook.ErrDeliveryDelivered):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type MemberBalanceResponse struct {
+	MemberID         uint                   `json:"member_id"`
+	Year             int                    `json:"year"`
+	GrantedDays      float32                `json:"granted_days"`
+	UsedDays         float32                `json:"used_days"`
+	ReservedDays     float32                `json:"reserved_days"`
+	RemainingDays    float32                `json:"remaining_days"`
+	ExpiringSoonDays float32                `json:"expiring_soon_days"`
+	Grants           []GrantBalanceResponse `json:"grants"`
+	PendingPlans     []VacationPlanResponse `json:"pending_plans"`
+}
+
+type GrantBalanceResponse struct {
+	GivenVacationID uint                  `json:"given_vacation_id"`
+	GenerateRule    string                `json:"generate_rule"`
+	GenerateNote    string                `json:"generate_note"`
+	GenerateDate    time.Time             `json:"generate_date"`
+	ExpireDate      time.Time             `json:"expire_date"`
+	IsExpired       bool                  `json:"is_expired"`
+	ExpiringSoon    bool                  `json:"expiring_soon"`
+	GivenDays       float32               `json:"given_days"`
+	UsedDays        float32               `json:"used_days"`
+	ReservedDays    float32               `json:"reserved_days"`
+	RemainingDays   float32               `json:"remaining_days"`
+	PendingPlans    []PendingPlanResponse `json:"pending_plans"`
+}
+
+type PendingPlanResponse struct {
+	VacationPlanID uint    `json:"vacation_plan_id"`
+	ReservedDays   float32 `json:"reserved_days"`
+}
@@ -1,11 +1,95 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
 
 type CompanyResponse struct {
-	ID                          uint      `json:"id"`
-	Name                        string    `json:"name"`
-	AccountingDay               time.Time `json:"accounting_day"`
-	VacationGenerateTypeName    string    `json:"vacation_generate_type_name"`
-	VacationGenerateDescription string    `json:"vacation_generate_description"`
+	ID                          uint                     `json:"id"`
+	Name                        string                   `json:"name"`
+	AccountingDay               time.Time                `json:"accounting_day"`
+	VacationGenerateTypeName    string                   `json:"vacation_generate_type_name"`
+	VacationGenerateDescription string                   `json:"vacation_generate_description"`
+	AdvanceVacationDays         float32                  `json:"advance_vacation_days"`
+	CarryOverPolicy             string                   `json:"carry_over_policy"`
+	CarryOverMaxDays            float32                  `json:"carry_over_max_days"`
+	PromotionSetting            PromotionSettingResponse `json:"promotion_setting"`
+}
+
+// 미리 당겨쓰기 허용 일수. 신청할 때 잔여일수를 넘어 이만큼까지 예약할 수 있다. 0 이면 잔여일수 안에서만 신청한다
+type AdvanceVacationRequest struct {
+	AdvanceVacationDays *float32 `json:"advance_vacation_days" validate:"required,min=0,max=25"`
+}
+
+// 촉진 설정 전체를 바꾼다. 오프셋은 법정 기한보다 앞당길 일수, response_days 는 0 이면 법정 기간이고
+// 법정 제출 기간(10일)보다 짧게 줄일 수 없다
+type PromotionSettingRequest struct {
+	Enabled                *bool `json:"enabled" validate:"required"`
+	FirstNoticeOffsetDays  int   `json:"first_notice_offset_days" validate:"min=0,max=10"`
+	SecondNoticeOffsetDays int   `json:"second_notice_offset_days" validate:"min=0,max=30"`
+	ResponseDays           int   `json:"response_days" validate:"omitempty,min=10,max=30"`
+	AutoProposeDates       *bool `json:"auto_propose_dates" validate:"required"`
+}
+
+type PromotionSettingResponse struct {
+	CompanyID              uint       `json:"company_id"`
+	Enabled                bool       `json:"enabled"`
+	FirstNoticeOffsetDays  int        `json:"first_notice_offset_days"`
+	SecondNoticeOffsetDays int        `json:"second_notice_offset_days"`
+	ResponseDays           int        `json:"response_days"`
+	AutoProposeDates       bool       `json:"auto_propose_dates"`
+	UpdatedAt              *time.Time `json:"updated_at"` // 저장한 적 없는 기본 설정이면 null
+	UpdatedBy              *uint      `json:"updated_by"`
+}
+
+func MapPromotionSettingToResponse(setting models.PromotionSetting) PromotionSettingResponse {
+	response := PromotionSettingResponse{
+		CompanyID:              setting.CompanyID,
+		Enabled:                setting.Enabled,
+		FirstNoticeOffsetDays:  setting.FirstNoticeOffsetDays,
+		SecondNoticeOffsetDays: setting.SecondNoticeOffsetDays,
+		ResponseDays:           setting.ResponseDays,
+		AutoProposeDates:       setting.AutoProposeDates,
+		UpdatedBy:              setting.UpdatedBy,
+	}
+	if setting.ID != 0 {
+		response.UpdatedAt = &setting.UpdatedAt
+	}
+	return response
+}
+
+// 결재 알림 설정 전체를 바꾼다. 일수는 결재 차례가 된 날부터 세고 0 이면 보내지 않는다. escalation_days 는 reminder_days 보다 커야 한다
+type ApprovalSettingRequest struct {
+	DigestEnabled  *bool `json:"digest_enabled" validate:"required"`
+	ReminderDays   int   `json:"reminder_days" validate:"min=0,max=30"`
+	EscalationDays int   `json:"escalation_days" validate:"min=0,max=60"`
+	// 결재자 없이 제출한 촉진 사용 계획 바로 승인 여부
+	AutoApprovePromotionPlans *bool `json:"auto_approve_promotion_plans" validate:"required"`
+}
+
+type ApprovalSettingResponse struct {
+	CompanyID                 uint       `json:"company_id"`
+	DigestEnabled             bool       `json:"digest_enabled"`
+	ReminderDays              int        `json:"reminder_days"`
+	EscalationDays            int        `json:"escalation_days"`
+	AutoApprovePromotionPlans bool       `json:"auto_approve_promotion_plans"`
+	UpdatedAt                 *time.Time `json:"updated_at"` // 저장한 적 없는 기본 설정이면 null
+	UpdatedBy                 *uint      `json:"updated_by"`
+}
+
+func MapApprovalSettingToResponse(setting models.ApprovalSetting) ApprovalSettingResponse {
+	response := ApprovalSettingResponse{
+		CompanyID:                 setting.CompanyID,
+		DigestEnabled:             setting.DigestEnabled,
+		ReminderDays:              setting.ReminderDays,
+		EscalationDays:            setting.EscalationDays,
+		AutoApprovePromotionPlans: setting.AutoApprovePromotionPlans,
+		UpdatedBy:                 setting.UpdatedBy,
+	}
+	if setting.ID != 0 {
+		response.UpdatedAt = &setting.UpdatedAt
+	}
+	return response
 }
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+type IssueDenyWorkRequest struct {
+	WorkDate time.Time `json:"work_date" validate:"required"`
+	Memo     string    `json:"memo" validate:"max=255"`
+}
+
+type DenyWorkResponse struct {
+	ID              uint       `json:"id"`
+	MemberID        uint       `json:"member_id"`
+	MemberName      string     `json:"member_name"`
+	GivenVacationID uint       `json:"given_vacation_id"`
+	ApplyVacationID uint       `json:"apply_vacation_id"`
+	WorkDate        time.Time  `json:"work_date"`
+	NotificationID  uint       `json:"notification_id"`
+	IssuedBy        *uint      `json:"issued_by"`
+	Memo            string     `json:"memo"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func MapDenyWorkToResponse(denyWork models.VacationDenyWork) DenyWorkResponse {
+	return DenyWorkResponse{
+		ID:              denyWork.ID,
+		MemberID:        denyWork.MemberID,
+		MemberName:      denyWork.Member.Name,
+		GivenVacationID: denyWork.GivenVacationID,
+		ApplyVacationID: denyWork.ApplyVacationID,
+		WorkDate:        denyWork.WorkDate,
+		NotificationID:  denyWork.NotificationID,
+		IssuedBy:        denyWork.IssuedBy,
+		Memo:            denyWork.Memo,
+		AcceptedAt:      denyWork.AcceptedAt,
+		CreatedAt:       denyWork.CreatedAt,
+	}
+}
+
+func MapDenyWorksToResponse(denyWorks []models.VacationDenyWork) []DenyWorkResponse {
+	responses := make([]DenyWorkResponse, 0, len(denyWorks))
+	for _, denyWork := range denyWorks {
+		responses = append(responses, MapDenyWorkToResponse(denyWork))
+	}
+	return responses
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type VerifyEvidenceResponse struct {
+	Valid      bool       `json:"valid"`       // 자료로 다시 계산한 해시가 자료에 적힌 해시와 같음
+	Hash       string     `json:"hash"`        // 다시 계산한 해시
+	Recorded   bool       `json:"recorded"`    // 이 회사에서 내보낸 기록이 있는 해시
+	ExportedAt *time.Time `json:"exported_at"` // 내보낸 시각
+}
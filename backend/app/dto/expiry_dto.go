@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+type VacationExpiryResponse struct {
+	ID                       uint      `json:"id"`
+	MemberID                 uint      `json:"member_id"`
+	GivenVacationID          uint      `json:"given_vacation_id"`
+	ExpireDate               time.Time `json:"expire_date"`
+	RemainingDays            float32   `json:"remaining_days"`
+	CarriedDays              float32   `json:"carried_days"`
+	ForfeitedDays            float32   `json:"forfeited_days"`
+	CarryOverPolicy          string    `json:"carry_over_policy"`
+	VacationPromotionStateID uint      `json:"vacation_promotion_state_id"`
+	PromotionCompleted       bool      `json:"promotion_completed"`
+	CarryOverVacationID      *uint     `json:"carry_over_vacation_id"`
+	Reason                   string    `json:"reason"`
+	CreatedAt                time.Time `json:"created_at"`
+}
+
+func MapVacationExpiryToResponse(expiry models.VacationExpiry) VacationExpiryResponse {
+	return VacationExpiryResponse{
+		ID:                       expiry.ID,
+		MemberID:                 expiry.MemberID,
+		GivenVacationID:          expiry.GivenVacationID,
+		ExpireDate:               expiry.ExpireDate,
+		RemainingDays:            expiry.RemainingDays,
+		CarriedDays:              expiry.CarriedDays,
+		ForfeitedDays:            expiry.ForfeitedDays,
+		CarryOverPolicy:          expiry.CarryOverPolicy,
+		VacationPromotionStateID: expiry.VacationPromotionStateID,
+		PromotionCompleted:       expiry.PromotionCompleted,
+		CarryOverVacationID:      expiry.CarryOverVacationID,
+		Reason:                   expiry.Reason,
+		CreatedAt:                expiry.CreatedAt,
+	}
+}
+
+func MapVacationExpiriesToResponse(expiries []models.VacationExpiry) []VacationExpiryResponse {
+	responses := make([]VacationExpiryResponse, 0, len(expiries))
+	for _, expiry := range expiries {
+		responses = append(responses, MapVacationExpiryToResponse(expiry))
+	}
+	return responses
+}
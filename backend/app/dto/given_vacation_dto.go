@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+type GivenVacationResponse struct {
+	ID                       uint      `json:"id"`
+	MemberID                 uint      `json:"member_id"`
+	VacationGenerateTypeID   uint      `json:"vacation_generate_type_id"`
+	VacationPromotionStateID uint      `json:"vacation_promotion_state_id"`
+	Year                     int       `json:"year"`
+	GivenDays                float32   `json:"given_days"`
+	GenerateDate             time.Time `json:"generate_date"`
+	ExpireDate               time.Time `json:"expire_date"`
+	GenerateRule             string    `json:"generate_rule"`
+	GenerateNote             string    `json:"generate_note"`
+	UsedDays                 float32   `json:"used_days"`
+	ReservedDays             float32   `json:"reserved_days"`
+	RemainingDays            float32   `json:"remaining_days"`
+	IsExpired                bool      `json:"is_expired"`
+}
+
+func MapGivenVacationToResponse(givenVacation models.GivenVacation) GivenVacationResponse {
+	return GivenVacationResponse{
+		ID:                       givenVacation.ID,
+		MemberID:                 givenVacation.MemberID,
+		VacationGenerateTypeID:   givenVacation.VacationGenerateTypeID,
+		VacationPromotionStateID: givenVacation.VacationPromotionStateID,
+		Year:                     givenVacation.Year,
+		GivenDays:                givenVacation.GivenDays,
+		GenerateDate:             givenVacation.GenerateDate,
+		ExpireDate:               givenVacation.ExpireDate,
+		GenerateRule:             givenVacation.GenerateRule,
+		GenerateNote:             givenVacation.GenerateNote,
+		UsedDays:                 givenVacation.UsedDays,
+		ReservedDays:             givenVacation.ReservedDays,
+		RemainingDays:            givenVacation.RemainingDays,
+		IsExpired:                givenVacation.IsExpired,
+	}
+}
+
+func MapGivenVacationsToResponse(givenVacations []models.GivenVacation) []GivenVacationResponse {
+	responses := make([]GivenVacationResponse, 0, len(givenVacations))
+	for _, givenVacation := range givenVacations {
+		responses = append(responses, MapGivenVacationToResponse(givenVacation))
+	}
+	return responses
+}
+
+type RetirementSettlementRequest struct {
+	DailyWage int64 `json:"daily_wage" validate:"gte=0"`
+	Apply     bool  `json:"apply"`
+}
+
+type RetirementSettlementResponse struct {
+	MemberID        uint                   `json:"member_id"`
+	GrantedDays     float32                `json:"granted_days"`
+	SettledDays     float32                `json:"settled_days"`
+	HireDateDays    float32                `json:"hire_date_days"`
+	DiffDays        float32                `json:"diff_days"`
+	DailyWage       int64                  `json:"daily_wage"`
+	Allowance       int64                  `json:"allowance"`
+	CorrectiveGrant *GivenVacationResponse `json:"corrective_grant,omitempty"`
+}
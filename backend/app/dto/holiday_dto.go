@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/models"
+)
+
+type HolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"required,max=60"`
+}
+
+type HolidayResponse struct {
+	ID         uint      `json:"id,omitempty"` // 관리자가 추가한 공휴일만
+	CompanyID  uint      `json:"company_id,omitempty"`
+	Date       time.Time `json:"date"`
+	Name       string    `json:"name"`
+	Substitute bool      `json:"substitute"`
+}
+
+type ImportHolidaysResponse struct {
+	Imported int               `json:"imported"`
+	Holidays []HolidayResponse `json:"holidays"`
+}
+
+type WorkingDaysResponse struct {
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	WorkingDays float32   `json:"working_days"`
+}
+
+func MapHolidaysToResponse(holidays []calendar.Holiday) []HolidayResponse {
+	response := make([]HolidayResponse, 0, len(holidays))
+	for _, holiday := range holidays {
+		response = append(response, HolidayResponse{
+			Date:       holiday.Date,
+			Name:       holiday.Name,
+			Substitute: holiday.Substitute,
+		})
+	}
+	return response
+}
+
+func MapPublicHolidayToResponse(holiday models.PublicHoliday) HolidayResponse {
+	return HolidayResponse{
+		ID:   holiday.ID,
+		Date: holiday.Date,
+		Name: holiday.Name,
+	}
+}
+
+func MapCompanyHolidayToResponse(holiday models.CompanyHoliday) HolidayResponse {
+	return HolidayResponse{
+		ID:        holiday.ID,
+		CompanyID: holiday.CompanyID,
+		Date:      holiday.Date,
+		Name:      holiday.Name,
+	}
+}
+
+func MapCompanyHolidaysToResponse(holidays []models.CompanyHoliday) []HolidayResponse {
+	response := make([]HolidayResponse, 0, len(holidays))
+	for _, holiday := range holidays {
+		response = append(response, MapCompanyHolidayToResponse(holiday))
+	}
+	return response
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/scheduler"
+)
+
+type JobResponse struct {
+	Name        string          `json:"name"`
+	Spec        string          `json:"spec"`
+	Description string          `json:"description"`
+	NextRun     time.Time       `json:"next_run"`
+	LastRun     *JobRunResponse `json:"last_run"`
+}
+
+type JobRunResponse struct {
+	ID         uint       `json:"id"`
+	JobName    string     `json:"job_name"`
+	InstanceID string     `json:"instance_id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error"`
+	Manual     bool       `json:"manual"`
+}
+
+func MapJobStatusToResponse(status scheduler.JobStatus) JobResponse {
+	return JobResponse{
+		Name:        status.Name,
+		Spec:        status.Spec,
+		Description: status.Description,
+		NextRun:     status.NextRun,
+		LastRun:     MapJobRunToResponse(status.LastRun),
+	}
+}
+
+func MapJobRunToResponse(run *models.JobRun) *JobRunResponse {
+	if run == nil {
+		return nil
+	}
+	return &JobRunResponse{
+		ID:         run.ID,
+		JobName:    run.JobName,
+		InstanceID: run.InstanceID,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Status:     run.Status,
+		Error:      run.Error,
+		Manual:     run.Manual,
+	}
+}
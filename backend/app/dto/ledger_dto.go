@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+type AdjustVacationRequest struct {
+	Days float32 `json:"days" validate:"required"`
+	Memo string  `json:"memo" validate:"required,max=255"`
+}
+
+type VacationLedgerResponse struct {
+	ID                   uint      `json:"id"`
+	GivenVacationID      uint      `json:"given_vacation_id"`
+	VacationLedgerTypeID uint      `json:"vacation_ledger_type_id"`
+	ApplyVacationID      *uint     `json:"apply_vacation_id"`
+	VacationPlanID       *uint     `json:"vacation_plan_id"`
+	Days                 float32   `json:"days"`
+	Memo                 string    `json:"memo"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+func MapVacationLedgerToResponse(entry models.VacationLedger) VacationLedgerResponse {
+	return VacationLedgerResponse{
+		ID:                   entry.ID,
+		GivenVacationID:      entry.GivenVacationID,
+		VacationLedgerTypeID: entry.VacationLedgerTypeID,
+		ApplyVacationID:      entry.ApplyVacationID,
+		VacationPlanID:       entry.VacationPlanID,
+		Days:                 entry.Days,
+		Memo:                 entry.Memo,
+		CreatedAt:            entry.CreatedAt,
+	}
+}
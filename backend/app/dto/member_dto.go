@@ -11,6 +11,7 @@ type CreateMemberRequest struct {
 	Email    string    `json:"email" validate:"required,email"`
 	Password string    `json:"password" validate:"required"`
 	HireDate time.Time `json:"hire_date" validate:"required"`
+	Language string    `json:"language" validate:"omitempty,oneof=ko en"` // 이메일 알림 언어. 기본 ko
 }
 type MemberResponse struct {
 	ID       uint      `json:"id"`
@@ -18,6 +19,7 @@ type MemberResponse struct {
 	Email    string    `json:"email"`
 	HireDate time.Time `json:"hire_date"`
 	IsActive bool      `json:"is_active"`
+	Language string    `json:"language"`
 }
 
 func MapMemberToDTO(member *models.Member) MemberResponse {
@@ -30,6 +32,7 @@ func MapMemberToDTO(member *models.Member) MemberResponse {
 		Email:    member.Email,
 		HireDate: member.HireDate,
 		IsActive: member.IsActive,
+		Language: member.Language,
 	}
 }
 
@@ -0,0 +1,188 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+)
+
+type NotificationResponse struct {
+	ID                 uint       `json:"id"`
+	NotificationTypeID uint       `json:"notification_type_id"`
+	NotificationType   string     `json:"notification_type"`
+	Contents           string     `json:"contents"`
+	GivenVacationID    *uint      `json:"given_vacation_id"`
+	VacationPlanID     *uint      `json:"vacation_plan_id"`
+	ApplyVacationID    *uint      `json:"apply_vacation_id"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ReadAt             *time.Time `json:"read_at"`
+	Acknowledged       bool       `json:"acknowledged"`
+	AcknowledgedAt     *time.Time `json:"acknowledged_at"`
+}
+
+type NotificationInboxResponse struct {
+	Items  []NotificationResponse `json:"items"`
+	Page   int                    `json:"page"`
+	Size   int                    `json:"size"`
+	Total  int64                  `json:"total"`
+	Unread int64                  `json:"unread"` // 필터와 관계없이 읽지 않은 알림 수
+}
+
+type ReadAllNotificationsResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+func MapNotificationToResponse(recipient models.NotificationMember) NotificationResponse {
+	notification := recipient.Notification
+	return NotificationResponse{
+		ID:                 notification.ID,
+		NotificationTypeID: notification.NotificationTypeID,
+		NotificationType:   notification.NotificationType.TypeName,
+		Contents:           notification.Contents,
+		GivenVacationID:    notification.GivenVacationID,
+		VacationPlanID:     notification.VacationPlanID,
+		ApplyVacationID:    notification.ApplyVacationID,
+		CreatedAt:          notification.CreatedAt,
+		ReadAt:             recipient.ReadAt,
+		Acknowledged:       recipient.IsApprove,
+		AcknowledgedAt:     recipient.AcknowledgedAt,
+	}
+}
+
+func MapInboxToResponse(inbox notify.Inbox) NotificationInboxResponse {
+	response := NotificationInboxResponse{
+		Items:  make([]NotificationResponse, 0, len(inbox.Items)),
+		Page:   inbox.Page.Number,
+		Size:   inbox.Page.Size,
+		Total:  inbox.Total,
+		Unread: inbox.Unread,
+	}
+	for _, recipient := range inbox.Items {
+		response.Items = append(response.Items, MapNotificationToResponse(recipient))
+	}
+	return response
+}
+
+type NotificationDeliveryResponse struct {
+	ID                 uint       `json:"id"`
+	NotificationID     uint       `json:"notification_id"`
+	NotificationTypeID uint       `json:"notification_type_id"`
+	NotificationType   string     `json:"notification_type"`
+	MemberID           uint       `json:"member_id"`
+	MemberName         string     `json:"member_name"`
+	Channel            string     `json:"channel"`
+	Address            string     `json:"address"`
+	Status             string     `json:"status"` // pending, sent, failed
+	Digest             string     `json:"digest"` // 묶음 발송 주기 (daily, weekly). 비어 있으면 바로 보낸다
+	Attempts           int        `json:"attempts"`
+	NextAttemptAt      *time.Time `json:"next_attempt_at"`
+	LastError          string     `json:"last_error"`
+	SentAt             *time.Time `json:"sent_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+type NotificationDeliveryPageResponse struct {
+	Items []NotificationDeliveryResponse `json:"items"`
+	Page  int                            `json:"page"`
+	Size  int                            `json:"size"`
+	Total int64                          `json:"total"`
+}
+
+func MapNotificationDeliveryToResponse(delivery models.NotificationDelivery) NotificationDeliveryResponse {
+	return NotificationDeliveryResponse{
+		ID:                 delivery.ID,
+		NotificationID:     delivery.NotificationID,
+		NotificationTypeID: delivery.Notification.NotificationTypeID,
+		NotificationType:   delivery.Notification.NotificationType.TypeName,
+		MemberID:           delivery.MemberID,
+		MemberName:         delivery.Member.Name,
+		Channel:            delivery.Channel,
+		Address:            delivery.Address,
+		Status:             delivery.Status,
+		Digest:             delivery.Digest,
+		Attempts:           delivery.Attempts,
+		NextAttemptAt:      delivery.NextAttemptAt,
+		LastError:          delivery.LastError,
+		SentAt:             delivery.SentAt,
+		CreatedAt:          delivery.CreatedAt,
+	}
+}
+
+func MapDeliveryPageToResponse(page notify.DeliveryPage) NotificationDeliveryPageResponse {
+	response := NotificationDeliveryPageResponse{
+		Items: make([]NotificationDeliveryResponse, 0, len(page.Items)),
+		Page:  page.Page.Number,
+		Size:  page.Page.Size,
+		Total: page.Total,
+	}
+	for _, delivery := range page.Items {
+		response.Items = append(response.Items, MapNotificationDeliveryToResponse(delivery))
+	}
+	return response
+}
+
+// VacationPlanEventResponse 는 휴가 계획 상태가 바뀌었을 때 스트림으로 보내는 내용이다.
+type VacationPlanEventResponse struct {
+	VacationPlanID uint      `json:"vacation_plan_id"`
+	MemberID       uint      `json:"member_id"`
+	State          string    `json:"state"` // applied, approved, approval_canceled, rejected, rejection_canceled
+	ApproveStage   uint      `json:"approve_stage"`
+	CompleteState  bool      `json:"complete_state"`
+	RejectState    bool      `json:"reject_state"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+type NotificationPreferenceRequest struct {
+	NotificationTypeID uint   `json:"notification_type_id" validate:"required"`
+	InApp              *bool  `json:"in_app" validate:"required"`
+	Email              *bool  `json:"email" validate:"required"`
+	Webhook            *bool  `json:"webhook" validate:"required"`
+	Digest             string `json:"digest" validate:"required,oneof=immediate daily weekly"`
+}
+
+// 방해 금지 시간은 "HH:MM" 형식으로 시작과 종료를 함께 지정하거나 둘 다 비운다.
+// preferences 에 없는 알림 종류의 설정은 바꾸지 않는다.
+type NotificationSettingsRequest struct {
+	QuietHoursStart *string                         `json:"quiet_hours_start" validate:"omitempty,datetime=15:04"`
+	QuietHoursEnd   *string                         `json:"quiet_hours_end" validate:"omitempty,datetime=15:04"`
+	Preferences     []NotificationPreferenceRequest `json:"preferences" validate:"dive"`
+}
+
+type NotificationPreferenceResponse struct {
+	NotificationTypeID uint   `json:"notification_type_id"`
+	NotificationType   string `json:"notification_type"`
+	InApp              bool   `json:"in_app"`
+	Email              bool   `json:"email"`
+	Webhook            bool   `json:"webhook"`
+	Digest             string `json:"digest"`
+	Mandatory          bool   `json:"mandatory"` // 법정 촉진 알림. 설정과 관계없이 앱과 이메일로 바로 보낸다
+}
+
+type NotificationSettingsResponse struct {
+	MemberID        uint                             `json:"member_id"`
+	QuietHoursStart *string                          `json:"quiet_hours_start"`
+	QuietHoursEnd   *string                          `json:"quiet_hours_end"`
+	Preferences     []NotificationPreferenceResponse `json:"preferences"`
+}
+
+func MapNotificationSettingsToResponse(memberID uint, preferences notify.Preferences) NotificationSettingsResponse {
+	response := NotificationSettingsResponse{
+		MemberID:        memberID,
+		QuietHoursStart: preferences.Setting.QuietHoursStart,
+		QuietHoursEnd:   preferences.Setting.QuietHoursEnd,
+		Preferences:     make([]NotificationPreferenceResponse, 0, len(preferences.Items)),
+	}
+	for _, preference := range preferences.Items {
+		response.Preferences = append(response.Preferences, NotificationPreferenceResponse{
+			NotificationTypeID: preference.NotificationTypeID,
+			NotificationType:   preference.NotificationType.TypeName,
+			InApp:              preference.InApp,
+			Email:              preference.Email,
+			Webhook:            preference.Webhook,
+			Digest:             preference.Digest,
+			Mandatory:          notify.Mandatory(preference.NotificationTypeID),
+		})
+	}
+	return response
+}
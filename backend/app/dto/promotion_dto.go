@@ -0,0 +1,262 @@
+package dto
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/promotion"
+)
+
+type PromotionDeadlinesResponse struct {
+	Track           string     `json:"track"`
+	FirstNoticeFrom time.Time  `json:"first_notice_from"`
+	FirstNoticeBy   time.Time  `json:"first_notice_by"`
+	FirstNoticeDue  time.Time  `json:"first_notice_due"` // 회사 설정에 따라 앞당긴 기한
+	FirstNotifiedAt *time.Time `json:"first_notified_at"`
+	ResponseBy      time.Time  `json:"response_by"`
+	SecondNoticeBy  time.Time  `json:"second_notice_by"`
+	SecondNoticeDue time.Time  `json:"second_notice_due"` // 회사 설정에 따라 앞당긴 기한
+	ExpireDate      time.Time  `json:"expire_date"`
+}
+
+type PromotionHistoryResponse struct {
+	ID              uint      `json:"id"`
+	GivenVacationID uint      `json:"given_vacation_id"`
+	MemberID        uint      `json:"member_id"`
+	FromStateID     uint      `json:"from_state_id"`
+	ToStateID       uint      `json:"to_state_id"`
+	ActorID         *uint     `json:"actor_id"`
+	Reason          string    `json:"reason"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GrantPromotionResponse struct {
+	GivenVacation GivenVacationResponse      `json:"given_vacation"`
+	Applicable    bool                       `json:"applicable"`
+	Deadlines     PromotionDeadlinesResponse `json:"deadlines"`
+	Histories     []PromotionHistoryResponse `json:"histories"`
+}
+
+func MapPromotionDeadlinesToResponse(deadlines promotion.Deadlines) PromotionDeadlinesResponse {
+	return PromotionDeadlinesResponse{
+		Track:           deadlines.Track,
+		FirstNoticeFrom: deadlines.FirstNoticeFrom,
+		FirstNoticeBy:   deadlines.FirstNoticeBy,
+		FirstNoticeDue:  deadlines.FirstNoticeDue,
+		FirstNotifiedAt: deadlines.FirstNotifiedAt,
+		ResponseBy:      deadlines.ResponseBy,
+		SecondNoticeBy:  deadlines.SecondNoticeBy,
+		SecondNoticeDue: deadlines.SecondNoticeDue,
+		ExpireDate:      deadlines.ExpireDate,
+	}
+}
+
+func MapPromotionHistoryToResponse(history models.VacationPromotionHistory) PromotionHistoryResponse {
+	return PromotionHistoryResponse{
+		ID:              history.ID,
+		GivenVacationID: history.GivenVacationID,
+		MemberID:        history.MemberID,
+		FromStateID:     history.FromStateID,
+		ToStateID:       history.ToStateID,
+		ActorID:         history.ActorID,
+		Reason:          history.Reason,
+		CreatedAt:       history.CreatedAt,
+	}
+}
+
+func MapPromotionHistoriesToResponse(histories []models.VacationPromotionHistory) []PromotionHistoryResponse {
+	responses := make([]PromotionHistoryResponse, 0, len(histories))
+	for _, history := range histories {
+		responses = append(responses, MapPromotionHistoryToResponse(history))
+	}
+	return responses
+}
+
+type PromotionStatusResponse struct {
+	MemberID                   uint       `json:"member_id"`
+	MemberName                 string     `json:"member_name"`
+	OrganizeID                 *uint      `json:"organize_id"`
+	GivenVacationID            uint       `json:"given_vacation_id"`
+	GenerateRule               string     `json:"generate_rule"`
+	Track                      string     `json:"track"`
+	GenerateDate               time.Time  `json:"generate_date"`
+	ExpireDate                 time.Time  `json:"expire_date"`
+	VacationPromotionStateID   uint       `json:"vacation_promotion_state_id"`
+	VacationPromotionStateName string     `json:"vacation_promotion_state_name"`
+	GivenDays                  float32    `json:"given_days"`
+	UnusedDays                 float32    `json:"unused_days"`
+	ReservedDays               float32    `json:"reserved_days"`
+	FirstNotifiedAt            *time.Time `json:"first_notified_at"`
+	DeadlineType               string     `json:"deadline_type"`
+	Deadline                   *time.Time `json:"deadline"`
+	DaysOverdue                int        `json:"days_overdue"`
+	NeedsAction                bool       `json:"needs_action"`
+}
+
+type PromotionDashboardResponse struct {
+	Enabled     bool                      `json:"enabled"` // 회사의 촉진 사용 여부
+	Total       int                       `json:"total"`
+	NeedsAction int                       `json:"needs_action"`
+	Overdue     int                       `json:"overdue"`
+	StateCounts map[uint]int              `json:"state_counts"`
+	Tracks      []PromotionTrackResponse  `json:"tracks"`
+	Items       []PromotionStatusResponse `json:"items"`
+}
+
+// 촉진 일정(1년 이상 연차, 1년 미만 최초 9일, 마지막 2일)별 집계
+type PromotionTrackResponse struct {
+	Track       string       `json:"track"`
+	Total       int          `json:"total"`
+	NeedsAction int          `json:"needs_action"`
+	Overdue     int          `json:"overdue"`
+	StateCounts map[uint]int `json:"state_counts"`
+}
+
+func MapPromotionStatusToResponse(givenVacation models.GivenVacation, status promotion.Status) PromotionStatusResponse {
+	return PromotionStatusResponse{
+		MemberID:                   givenVacation.MemberID,
+		MemberName:                 givenVacation.Member.Name,
+		OrganizeID:                 givenVacation.Member.OrganizeID,
+		GivenVacationID:            givenVacation.ID,
+		GenerateRule:               givenVacation.GenerateRule,
+		Track:                      status.Deadlines.Track,
+		GenerateDate:               givenVacation.GenerateDate,
+		ExpireDate:                 givenVacation.ExpireDate,
+		VacationPromotionStateID:   givenVacation.VacationPromotionStateID,
+		VacationPromotionStateName: givenVacation.VacationPromotionState.TypeName,
+		GivenDays:                  givenVacation.GivenDays,
+		UnusedDays:                 givenVacation.RemainingDays,
+		ReservedDays:               givenVacation.ReservedDays,
+		FirstNotifiedAt:            status.Deadlines.FirstNotifiedAt,
+		DeadlineType:               status.DeadlineType,
+		Deadline:                   status.Deadline,
+		DaysOverdue:                status.DaysOverdue,
+		NeedsAction:                status.NeedsAction,
+	}
+}
+
+type SendPromotionNoticesRequest struct {
+	OrganizeID       *uint  `json:"organize_id"`        // 하위 조직 포함
+	GivenVacationIDs []uint `json:"given_vacation_ids"` // 비어 있으면 대상 전체
+}
+
+type PromotionNoticeResponse struct {
+	GivenVacationID  uint      `json:"given_vacation_id"`
+	GivenVacationIDs []uint    `json:"given_vacation_ids"` // 한 통지로 촉진하는 지급분 전체
+	MemberID         uint      `json:"member_id"`
+	MemberName       string    `json:"member_name"`
+	OrganizeID       *uint     `json:"organize_id"`
+	UnusedDays       float32   `json:"unused_days"`
+	ExpireDate       time.Time `json:"expire_date"`
+	ResponseBy       time.Time `json:"response_by"`
+	Contents         string    `json:"contents"`
+	NotificationID   uint      `json:"notification_id,omitempty"`
+}
+
+func MapPromotionNoticesToResponse(notices []promotion.Notice) []PromotionNoticeResponse {
+	responses := make([]PromotionNoticeResponse, 0, len(notices))
+	for _, notice := range notices {
+		responses = append(responses, PromotionNoticeResponse{
+			GivenVacationID:  notice.GivenVacation.ID,
+			GivenVacationIDs: grantIDs(notice.Grants),
+			MemberID:         notice.Member.ID,
+			MemberName:       notice.Member.Name,
+			OrganizeID:       notice.Member.OrganizeID,
+			UnusedDays:       notice.UnusedDays,
+			ExpireDate:       notice.Deadlines.ExpireDate,
+			ResponseBy:       notice.Deadlines.ResponseBy,
+			Contents:         notice.Contents,
+			NotificationID:   notice.NotificationID,
+		})
+	}
+	return responses
+}
+
+type PromotionPlanRequest struct {
+	Vacations     []PromotionVacationRequest `json:"vacations" validate:"required,min=1,dive"`
+	ApproverOrder []uint                     `json:"approver_order"` // 비어 있으면 회사 결재 설정이 허용할 때만 바로 승인 완료
+}
+
+type PromotionVacationRequest struct {
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required,gtefield=StartDate"`
+	HalfFirst bool      `json:"half_first"`
+	HalfLast  bool      `json:"half_last"`
+}
+
+type PromotionPlanResponse struct {
+	Plan           VacationPlanResponse       `json:"plan"`
+	GivenVacations []GivenVacationResponse    `json:"given_vacations"` // 통지된 지급분별 남은 일수와 촉진 상태
+	PlannedDays    float32                    `json:"planned_days"`
+	UnplannedDays  float32                    `json:"unplanned_days"`
+	Histories      []PromotionHistoryResponse `json:"histories"`
+}
+
+type SendSecondNoticesRequest struct {
+	OrganizeID       *uint                         `json:"organize_id"`        // 하위 조직 포함
+	GivenVacationIDs []uint                        `json:"given_vacation_ids"` // 비어 있으면 대상 전체. designations 만 있으면 지정한 지급분만
+	Designations     []PromotionDesignationRequest `json:"designations" validate:"dive"`
+}
+
+// 지급분별로 관리자가 직접 지정한 사용 시기. 묶음의 어느 지급분 ID 로든 지정할 수 있다. 없는 묶음은 제안 날짜로 지정
+type PromotionDesignationRequest struct {
+	GivenVacationID uint                       `json:"given_vacation_id" validate:"required"`
+	Vacations       []PromotionVacationRequest `json:"vacations" validate:"required,min=1,dive"`
+}
+
+type PromotionDesignationResponse struct {
+	GivenVacationID  uint                       `json:"given_vacation_id"`
+	GivenVacationIDs []uint                     `json:"given_vacation_ids"` // 한 통보로 지정하는 지급분 전체
+	MemberID         uint                       `json:"member_id"`
+	MemberName       string                     `json:"member_name"`
+	OrganizeID       *uint                      `json:"organize_id"`
+	UnusedDays       float32                    `json:"unused_days"`
+	DesignatedDays   float32                    `json:"designated_days"`
+	ExpireDate       time.Time                  `json:"expire_date"`
+	SecondNoticeBy   time.Time                  `json:"second_notice_by"`
+	Vacations        []PromotionVacationRequest `json:"vacations"`
+	Contents         string                     `json:"contents"`
+	NotificationID   uint                       `json:"notification_id,omitempty"`
+	VacationPlanID   uint                       `json:"vacation_plan_id,omitempty"`
+	Skipped          string                     `json:"skipped,omitempty"` // 일괄 발송에서 보내지 못한 이유
+}
+
+func MapPromotionDesignationsToResponse(designations []promotion.Designation) []PromotionDesignationResponse {
+	responses := make([]PromotionDesignationResponse, 0, len(designations))
+	for _, designation := range designations {
+		vacations := make([]PromotionVacationRequest, 0, len(designation.Vacations))
+		for _, vacation := range designation.Vacations {
+			vacations = append(vacations, PromotionVacationRequest{
+				StartDate: vacation.StartDate,
+				EndDate:   vacation.EndDate,
+				HalfFirst: vacation.HalfFirst,
+				HalfLast:  vacation.HalfLast,
+			})
+		}
+		responses = append(responses, PromotionDesignationResponse{
+			GivenVacationID:  designation.GivenVacation.ID,
+			GivenVacationIDs: grantIDs(designation.Grants),
+			MemberID:         designation.Member.ID,
+			MemberName:       designation.Member.Name,
+			OrganizeID:       designation.Member.OrganizeID,
+			UnusedDays:       designation.UnusedDays,
+			DesignatedDays:   designation.DesignatedDays,
+			ExpireDate:       designation.Deadlines.ExpireDate,
+			SecondNoticeBy:   designation.Deadlines.SecondNoticeBy,
+			Vacations:        vacations,
+			Contents:         designation.Contents,
+			NotificationID:   designation.NotificationID,
+			VacationPlanID:   designation.PlanID,
+			Skipped:          designation.Skipped,
+		})
+	}
+	return responses
+}
+
+func grantIDs(grants []*models.GivenVacation) []uint {
+	ids := make([]uint, 0, len(grants))
+	for _, grant := range grants {
+		ids = append(ids, grant.ID)
+	}
+	return ids
+}
@@ -36,15 +36,16 @@ type ApproveVacationPlanRequest struct {
 }
 
 type VacationPlanResponse struct {
-	ID            uint                    `json:"id"`
-	MemberID      uint                    `json:"member_id"`
-	MemberName    string                  `json:"member_name"`
-	ApplyDate     time.Time               `json:"apply_date"`
-	ApproverOrder []ApproverResponse      `json:"approver_order"`
-	Vacations     []ApplyVacationResponse `json:"vacations"`
-	ApproveStage  uint                    `json:"approve_stage"`
-	RejectState   bool                    `json:"reject_state"`
-	CompleteState bool                    `json:"complete_state"`
+	ID             uint                    `json:"id"`
+	MemberID       uint                    `json:"member_id"`
+	MemberName     string                  `json:"member_name"`
+	ApplyDate      time.Time               `json:"apply_date"`
+	ApproverOrder  []ApproverResponse      `json:"approver_order"`
+	Vacations      []ApplyVacationResponse `json:"vacations"`
+	ApproveStage   uint                    `json:"approve_stage"`
+	RejectState    bool                    `json:"reject_state"`
+	CompleteState  bool                    `json:"complete_state"`
+	NotificationID *uint                   `json:"notification_id"`
 }
 
 type ApproverResponse struct {
@@ -55,13 +56,14 @@ type ApproverResponse struct {
 }
 
 type ApplyVacationResponse struct {
-	ID           uint      `json:"id"`
-	StartDate    time.Time `json:"start_date"`
-	EndDate      time.Time `json:"end_date"`
-	HalfFirst    bool      `json:"half_first"`
-	HalfLast     bool      `json:"half_last"`
-	ApproveStage uint      `json:"approve_stage"`
-	RejectState  bool      `json:"reject_state"`
+	ID             uint      `json:"id"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	HalfFirst      bool      `json:"half_first"`
+	HalfLast       bool      `json:"half_last"`
+	ApproveStage   uint      `json:"approve_stage"`
+	RejectState    bool      `json:"reject_state"`
+	VacationTypeID uint      `json:"vacation_type_id"`
 }
 
 type ApplyVacationCardResponse struct {
@@ -79,12 +81,13 @@ type ApplyVacationCardResponse struct {
 
 func MapApplyVacationToResponse(vacation models.ApplyVacation) ApplyVacationResponse {
 	return ApplyVacationResponse{
-		ID:           vacation.ID,
-		StartDate:    vacation.StartDate,
-		EndDate:      vacation.EndDate,
-		HalfFirst:    vacation.HalfFirst,
-		ApproveStage: vacation.ApproveStage,
-		RejectState:  vacation.RejectState,
+		ID:             vacation.ID,
+		StartDate:      vacation.StartDate,
+		EndDate:        vacation.EndDate,
+		HalfFirst:      vacation.HalfFirst,
+		ApproveStage:   vacation.ApproveStage,
+		RejectState:    vacation.RejectState,
+		VacationTypeID: vacation.VacationTypeID,
 	}
 }
 
@@ -105,15 +108,16 @@ func MapApplyVacationToCardResponse(vacation models.ApplyVacation, completeState
 
 func MapVacationPlanToResponse(plan models.VacationPlan) VacationPlanResponse {
 	return VacationPlanResponse{
-		ID:            plan.ID,
-		MemberID:      plan.MemberID,
-		MemberName:    plan.Member.Name,
-		ApplyDate:     plan.ApplyDate,
-		ApproverOrder: nil,
-		Vacations:     nil,
-		ApproveStage:  plan.ApproveStage,
-		RejectState:   plan.RejectState,
-		CompleteState: plan.CompleteState,
+		ID:             plan.ID,
+		MemberID:       plan.MemberID,
+		MemberName:     plan.Member.Name,
+		ApplyDate:      plan.ApplyDate,
+		ApproverOrder:  nil,
+		Vacations:      nil,
+		ApproveStage:   plan.ApproveStage,
+		RejectState:    plan.RejectState,
+		CompleteState:  plan.CompleteState,
+		NotificationID: plan.NotificationID,
 	}
 }
 
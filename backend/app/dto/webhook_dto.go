@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+)
+
+// events 는 구독할 이벤트 목록이다. ["*"] 이면 전체를 구독한다
+type WebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=500"` // 공개 https 주소만. 개발용 WEBHOOK_ALLOW_INSECURE 설정이면 http, 내부 주소도 허용
+	Events      []string `json:"events" validate:"required,min=1"`
+	Description string   `json:"description" validate:"max=200"`
+	Active      *bool    `json:"active" validate:"required"`
+}
+
+type WebhookResponse struct {
+	ID          uint      `json:"id"`
+	CompanyID   uint      `json:"company_id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"` // 만들거나 새로 발급할 때만 보여 준다
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	WebhookID      uint       `json:"webhook_id"`
+	URL            string     `json:"url"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"` // pending, delivered, dead
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookDeliveryPageResponse struct {
+	Items []WebhookDeliveryResponse `json:"items"`
+	Page  int                       `json:"page"`
+	Size  int                       `json:"size"`
+	Total int64                     `json:"total"`
+}
+
+func MapWebhookToResponse(endpoint models.WebhookEndpoint, withSecret bool) WebhookResponse {
+	response := WebhookResponse{
+		ID:          endpoint.ID,
+		CompanyID:   endpoint.CompanyID,
+		URL:         endpoint.URL,
+		Events:      strings.Split(endpoint.Events, ","),
+		Description: endpoint.Description,
+		Active:      endpoint.Active,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+	if withSecret {
+		response.Secret = endpoint.Secret
+	}
+	return response
+}
+
+func MapWebhookDeliveryToResponse(delivery models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookEndpointID,
+		URL:            delivery.WebhookEndpoint.URL,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func MapWebhookDeliveryPageToResponse(page webhook.DeliveryPage) WebhookDeliveryPageResponse {
+	response := WebhookDeliveryPageResponse{
+		Items: make([]WebhookDeliveryResponse, 0, len(page.Items)),
+		Page:  page.Page.Number,
+		Size:  page.Page.Size,
+		Total: page.Total,
+	}
+	for _, delivery := range page.Items {
+		response.Items = append(response.Items, MapWebhookDeliveryToResponse(delivery))
+	}
+	return response
+}
@@ -41,6 +41,15 @@ const (
 	VacationCancelStateRequested = 2
 	VacationCancelStateCompleted = 3
 
+	//휴가 원장 타입
+	VacationLedgerTypeGrant     = 1
+	VacationLedgerTypeReserve   = 2
+	VacationLedgerTypeConsume   = 3
+	VacationLedgerTypeRelease   = 4
+	VacationLedgerTypeAdjust    = 5
+	VacationLedgerTypeExpire    = 6
+	VacationLedgerTypeCarryOver = 7
+
 	//알림 타입
 	NotificationTypeNormal                        = 1
 	NotificationTypeVacationApplied               = 2
@@ -50,4 +59,6 @@ const (
 	NotificationTypeVacationSecondPromotionAccept = 6
 	NotificationTypeVacationDenyWork              = 7
 	NotificationTypeVacationDenyWorkAccept        = 8
+	NotificationTypeApprovalDigest                = 9
+	NotificationTypeApprovalReminder              = 10
 )
@@ -0,0 +1,411 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/promotion"
+	"gorm.io/gorm"
+)
+
+// 연차 사용 촉진 소명 자료
+// 지급분별로 촉진 기한, 통지(발송, 읽음, 확인 시각), 사용 계획, 지정 사용 시기, 노무수령 거부 통지, 상태 이력, 소멸 기록을 묶는다.
+// 내보낸 자료는 SHA-256 해시를 함께 기록해 이후 변조 여부를 확인할 수 있다.
+
+const HashAlgorithm = "sha256"
+
+var ErrInvalidArchive = errors.New("소명 자료 형식이 올바르지 않습니다")
+
+// Scope 는 내보내기 범위이다. 비어 있는 조건은 적용하지 않는다.
+type Scope struct {
+	CompanyID uint  `json:"company_id"`
+	MemberID  *uint `json:"member_id,omitempty"`
+	Year      *int  `json:"year,omitempty"` // 지급일 기준 연도
+}
+
+type Archive struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Scope       Scope     `json:"scope"`
+	Grants      []Grant   `json:"grants"`
+}
+
+type Grant struct {
+	GivenVacationID          uint       `json:"given_vacation_id"`
+	MemberID                 uint       `json:"member_id"`
+	MemberName               string     `json:"member_name"`
+	MemberEmail              string     `json:"member_email"`
+	GenerateRule             string     `json:"generate_rule"`
+	GenerateDate             time.Time  `json:"generate_date"`
+	ExpireDate               time.Time  `json:"expire_date"`
+	GivenDays                float32    `json:"given_days"`
+	UsedDays                 float32    `json:"used_days"`
+	RemainingDays            float32    `json:"remaining_days"`
+	IsExpired                bool       `json:"is_expired"`
+	VacationPromotionStateID uint       `json:"vacation_promotion_state_id"`
+	VacationPromotionState   string     `json:"vacation_promotion_state"`
+	Deadlines                Deadlines  `json:"deadlines"`
+	Compliance               Compliance `json:"compliance"`
+	Notices                  []Notice   `json:"notices"`
+	Plans                    []Plan     `json:"plans"`
+	DenyWorks                []DenyWork `json:"deny_works"`
+	Histories                []History  `json:"histories"`
+	Expiries                 []Expiry   `json:"expiries"`
+}
+
+type Deadlines struct {
+	Track           string     `json:"track"`
+	FirstNoticeFrom time.Time  `json:"first_notice_from"`
+	FirstNoticeBy   time.Time  `json:"first_notice_by"`
+	FirstNotifiedAt *time.Time `json:"first_notified_at"`
+	ResponseBy      time.Time  `json:"response_by"`
+	SecondNoticeBy  time.Time  `json:"second_notice_by"`
+	ExpireDate      time.Time  `json:"expire_date"`
+}
+
+// Compliance 는 통지가 법정 기한 안에 이루어졌는지이다.
+type Compliance struct {
+	FirstNoticeSentAt   *time.Time `json:"first_notice_sent_at"`
+	FirstNoticeOnTime   bool       `json:"first_notice_on_time"`
+	SecondNoticeSentAt  *time.Time `json:"second_notice_sent_at"`
+	SecondNoticeOnTime  bool       `json:"second_notice_on_time"`
+	SecondNoticeSkipped bool       `json:"second_notice_skipped"` // 1차 촉진에서 미사용 일수를 모두 계획해 2차 촉진이 필요 없음
+}
+
+type Notice struct {
+	NotificationID     uint        `json:"notification_id"`
+	NotificationTypeID uint        `json:"notification_type_id"`
+	NotificationType   string      `json:"notification_type"`
+	Contents           string      `json:"contents"`
+	SentAt             time.Time   `json:"sent_at"`
+	Recipients         []Recipient `json:"recipients"`
+}
+
+type Recipient struct {
+	MemberID       uint       `json:"member_id"`
+	MemberName     string     `json:"member_name"`
+	ReadAt         *time.Time `json:"read_at"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+type Plan struct {
+	VacationPlanID uint       `json:"vacation_plan_id"`
+	NotificationID uint       `json:"notification_id"`
+	Designated     bool       `json:"designated"` // 회사가 지정한 사용 시기(2차 촉진)
+	ApplyDate      time.Time  `json:"apply_date"`
+	CompleteState  bool       `json:"complete_state"`
+	RejectState    bool       `json:"reject_state"`
+	Vacations      []Vacation `json:"vacations"`
+}
+
+type Vacation struct {
+	ApplyVacationID uint      `json:"apply_vacation_id"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	HalfFirst       bool      `json:"half_first"`
+	HalfLast        bool      `json:"half_last"`
+	RejectState     bool      `json:"reject_state"`
+}
+
+type DenyWork struct {
+	ID              uint       `json:"id"`
+	ApplyVacationID uint       `json:"apply_vacation_id"`
+	WorkDate        time.Time  `json:"work_date"`
+	NotificationID  uint       `json:"notification_id"`
+	IssuedBy        *uint      `json:"issued_by"`
+	Memo            string     `json:"memo"`
+	IssuedAt        time.Time  `json:"issued_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+}
+
+type History struct {
+	FromStateID uint      `json:"from_state_id"`
+	FromState   string    `json:"from_state"`
+	ToStateID   uint      `json:"to_state_id"`
+	ToState     string    `json:"to_state"`
+	ActorID     *uint     `json:"actor_id"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Expiry struct {
+	ExpireDate         time.Time `json:"expire_date"`
+	RemainingDays      float32   `json:"remaining_days"`
+	CarriedDays        float32   `json:"carried_days"`
+	ForfeitedDays      float32   `json:"forfeited_days"`
+	PromotionCompleted bool      `json:"promotion_completed"`
+	Reason             string    `json:"reason"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Sealed 는 해시와 함께 내보내는 JSON 자료이다. Archive 는 공백 없는 JSON 이고 Hash 는 그 SHA-256 이다.
+type Sealed struct {
+	HashAlgorithm string          `json:"hash_algorithm"`
+	Hash          string          `json:"hash"`
+	Archive       json.RawMessage `json:"archive"`
+}
+
+// Build 는 범위 안의 촉진 대상 지급분의 소명 자료를 모은다.
+func Build(tx *gorm.DB, scope Scope, now time.Time) (Archive, error) {
+	archive := Archive{GeneratedAt: now, Scope: scope, Grants: make([]Grant, 0)}
+
+	query := tx.Model(&models.GivenVacation{}).
+		Joins("JOIN members ON members.id = given_vacations.member_id").
+		Where("members.company_id = ?", scope.CompanyID).
+		Preload("Member").
+		Order("given_vacations.member_id ASC, given_vacations.generate_date ASC, given_vacations.id ASC")
+	if scope.MemberID != nil {
+		query = query.Where("given_vacations.member_id = ?", *scope.MemberID)
+	}
+	if scope.Year != nil {
+		from := time.Date(*scope.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		query = query.Where("given_vacations.generate_date >= ? AND given_vacations.generate_date < ?", from, from.AddDate(1, 0, 0))
+	}
+
+	var grants []models.GivenVacation
+	if err := query.Find(&grants).Error; err != nil {
+		return archive, err
+	}
+
+	var states []models.VacationPromotionState
+	if err := tx.Find(&states).Error; err != nil {
+		return archive, err
+	}
+	stateNames := make(map[uint]string, len(states))
+	for _, state := range states {
+		stateNames[state.ID] = state.TypeName
+	}
+	setting, err := promotion.LoadSetting(tx, scope.CompanyID)
+	if err != nil {
+		return archive, err
+	}
+
+	for _, grant := range grants {
+		if !promotion.Applicable(grant) {
+			continue
+		}
+		evidence, err := buildGrant(tx, grant, stateNames, setting)
+		if err != nil {
+			return archive, err
+		}
+		archive.Grants = append(archive.Grants, evidence)
+	}
+	return archive, nil
+}
+
+func buildGrant(tx *gorm.DB, grant models.GivenVacation, stateNames map[uint]string, setting models.PromotionSetting) (Grant, error) {
+	evidence := Grant{
+		GivenVacationID:          grant.ID,
+		MemberID:                 grant.MemberID,
+		MemberName:               grant.Member.Name,
+		MemberEmail:              grant.Member.Email,
+		GenerateRule:             grant.GenerateRule,
+		GenerateDate:             grant.GenerateDate,
+		ExpireDate:               grant.ExpireDate,
+		GivenDays:                grant.GivenDays,
+		UsedDays:                 grant.UsedDays,
+		RemainingDays:            grant.RemainingDays,
+		IsExpired:                grant.IsExpired,
+		VacationPromotionStateID: grant.VacationPromotionStateID,
+		VacationPromotionState:   stateNames[grant.VacationPromotionStateID],
+		Notices:                  make([]Notice, 0),
+		Plans:                    make([]Plan, 0),
+		DenyWorks:                make([]DenyWork, 0),
+		Histories:                make([]History, 0),
+		Expiries:                 make([]Expiry, 0),
+	}
+
+	firstNotifiedAt, err := promotion.FirstNotifiedAt(tx, grant.ID)
+	if err != nil {
+		return evidence, err
+	}
+	deadlines := promotion.DeadlinesOf(grant, firstNotifiedAt, setting)
+	evidence.Deadlines = Deadlines{
+		Track:           deadlines.Track,
+		FirstNoticeFrom: deadlines.FirstNoticeFrom,
+		FirstNoticeBy:   deadlines.FirstNoticeBy,
+		FirstNotifiedAt: deadlines.FirstNotifiedAt,
+		ResponseBy:      deadlines.ResponseBy,
+		SecondNoticeBy:  deadlines.SecondNoticeBy,
+		ExpireDate:      deadlines.ExpireDate,
+	}
+
+	// 여러 지급분을 한 통지로 촉진했으면 통지는 첫 지급분에 달려 있으므로 촉진 이력으로도 찾는다
+	var notifications []models.Notification
+	if err := tx.Where("given_vacation_id = ? OR id IN (?)", grant.ID,
+		tx.Model(&models.VacationPromotionHistory{}).Select("notification_id").
+			Where("given_vacation_id = ? AND notification_id IS NOT NULL", grant.ID)).
+		Preload("NotificationType").
+		Preload("NotificationMembers", func(db *gorm.DB) *gorm.DB { return db.Order("member_id ASC") }).
+		Preload("NotificationMembers.Member").
+		Order("created_at ASC, id ASC").
+		Find(&notifications).Error; err != nil {
+		return evidence, err
+	}
+	notificationIDs := make([]uint, 0, len(notifications))
+	designatedBy := make(map[uint]bool)
+	for _, notification := range notifications {
+		notificationIDs = append(notificationIDs, notification.ID)
+		notice := Notice{
+			NotificationID:     notification.ID,
+			NotificationTypeID: notification.NotificationTypeID,
+			NotificationType:   notification.NotificationType.TypeName,
+			Contents:           notification.Contents,
+			SentAt:             notification.CreatedAt,
+			Recipients:         make([]Recipient, 0, len(notification.NotificationMembers)),
+		}
+		for _, recipient := range notification.NotificationMembers {
+			notice.Recipients = append(notice.Recipients, Recipient{
+				MemberID:       recipient.MemberID,
+				MemberName:     recipient.Member.Name,
+				ReadAt:         recipient.ReadAt,
+				Acknowledged:   recipient.IsApprove,
+				AcknowledgedAt: recipient.AcknowledgedAt,
+			})
+		}
+		evidence.Notices = append(evidence.Notices, notice)
+
+		sentAt := notification.CreatedAt
+		switch notification.NotificationTypeID {
+		case enums.NotificationTypeVacationFirstPromotion:
+			if evidence.Compliance.FirstNoticeSentAt == nil {
+				evidence.Compliance.FirstNoticeSentAt = &sentAt
+			}
+		case enums.NotificationTypeVacationSecondPromotion:
+			designatedBy[notification.ID] = true
+			if evidence.Compliance.SecondNoticeSentAt == nil {
+				evidence.Compliance.SecondNoticeSentAt = &sentAt
+			}
+		}
+	}
+	evidence.Compliance.FirstNoticeOnTime = onTime(evidence.Compliance.FirstNoticeSentAt, evidence.Deadlines.FirstNoticeFrom, evidence.Deadlines.FirstNoticeBy)
+	evidence.Compliance.SecondNoticeOnTime = onTime(evidence.Compliance.SecondNoticeSentAt, evidence.Deadlines.FirstNoticeFrom, evidence.Deadlines.SecondNoticeBy)
+	evidence.Compliance.SecondNoticeSkipped = evidence.Compliance.SecondNoticeSentAt == nil &&
+		grant.VacationPromotionStateID == enums.VacationPromotionStateFirstComplete
+
+	if len(notificationIDs) > 0 {
+		var plans []models.VacationPlan
+		if err := tx.Where("notification_id IN ?", notificationIDs).
+			Preload("ApplyVacations", func(db *gorm.DB) *gorm.DB { return db.Order("start_date ASC, id ASC") }).
+			Order("apply_date ASC, id ASC").
+			Find(&plans).Error; err != nil {
+			return evidence, err
+		}
+		for _, plan := range plans {
+			item := Plan{
+				VacationPlanID: plan.ID,
+				NotificationID: *plan.NotificationID,
+				Designated:     designatedBy[*plan.NotificationID],
+				ApplyDate:      plan.ApplyDate,
+				CompleteState:  plan.CompleteState,
+				RejectState:    plan.RejectState,
+				Vacations:      make([]Vacation, 0, len(plan.ApplyVacations)),
+			}
+			for _, vacation := range plan.ApplyVacations {
+				item.Vacations = append(item.Vacations, Vacation{
+					ApplyVacationID: vacation.ID,
+					StartDate:       vacation.StartDate,
+					EndDate:         vacation.EndDate,
+					HalfFirst:       vacation.HalfFirst,
+					HalfLast:        vacation.HalfLast,
+					RejectState:     vacation.RejectState,
+				})
+			}
+			evidence.Plans = append(evidence.Plans, item)
+		}
+	}
+
+	var denyWorks []models.VacationDenyWork
+	if err := tx.Where("given_vacation_id = ?", grant.ID).Order("work_date ASC, id ASC").Find(&denyWorks).Error; err != nil {
+		return evidence, err
+	}
+	for _, denyWork := range denyWorks {
+		evidence.DenyWorks = append(evidence.DenyWorks, DenyWork{
+			ID:              denyWork.ID,
+			ApplyVacationID: denyWork.ApplyVacationID,
+			WorkDate:        denyWork.WorkDate,
+			NotificationID:  denyWork.NotificationID,
+			IssuedBy:        denyWork.IssuedBy,
+			Memo:            denyWork.Memo,
+			IssuedAt:        denyWork.CreatedAt,
+			AcceptedAt:      denyWork.AcceptedAt,
+		})
+	}
+
+	histories, err := promotion.Histories(tx, grant.ID)
+	if err != nil {
+		return evidence, err
+	}
+	for _, history := range histories {
+		evidence.Histories = append(evidence.Histories, History{
+			FromStateID: history.FromStateID,
+			FromState:   stateNames[history.FromStateID],
+			ToStateID:   history.ToStateID,
+			ToState:     stateNames[history.ToStateID],
+			ActorID:     history.ActorID,
+			Reason:      history.Reason,
+			CreatedAt:   history.CreatedAt,
+		})
+	}
+
+	var expiries []models.VacationExpiry
+	if err := tx.Where("given_vacation_id = ?", grant.ID).Order("id ASC").Find(&expiries).Error; err != nil {
+		return evidence, err
+	}
+	for _, expiry := range expiries {
+		evidence.Expiries = append(evidence.Expiries, Expiry{
+			ExpireDate:         expiry.ExpireDate,
+			RemainingDays:      expiry.RemainingDays,
+			CarriedDays:        expiry.CarriedDays,
+			ForfeitedDays:      expiry.ForfeitedDays,
+			PromotionCompleted: expiry.PromotionCompleted,
+			Reason:             expiry.Reason,
+			CreatedAt:          expiry.CreatedAt,
+		})
+	}
+	return evidence, nil
+}
+
+// onTime 은 sentAt 이 from 부터 by 까지(양 끝 날짜 포함)인지 확인한다.
+func onTime(sentAt *time.Time, from, by time.Time) bool {
+	if sentAt == nil {
+		return false
+	}
+	day := calendar.DateOnly(*sentAt)
+	return !day.Before(from) && !day.After(by)
+}
+
+// Seal 은 자료를 JSON 으로 만들고 해시를 계산한다.
+func Seal(archive Archive) (Sealed, error) {
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return Sealed{}, err
+	}
+	return Sealed{HashAlgorithm: HashAlgorithm, Hash: Hash(data), Archive: data}, nil
+}
+
+// Verify 는 내보낸 자료의 해시를 다시 계산해 기록된 해시와 같은지 확인하고 계산한 해시를 반환한다.
+// 공백, 들여쓰기 차이는 무시한다.
+func Verify(sealed Sealed) (bool, string, error) {
+	if sealed.HashAlgorithm != HashAlgorithm || len(sealed.Archive) == 0 {
+		return false, "", ErrInvalidArchive
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, sealed.Archive); err != nil {
+		return false, "", ErrInvalidArchive
+	}
+	hash := Hash(compact.Bytes())
+	return hash == sealed.Hash, hash, nil
+}
+
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
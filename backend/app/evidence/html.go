@@ -0,0 +1,153 @@
+package evidence
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"time"
+)
+
+// HTML 은 브라우저에서 바로 열어 인쇄할 수 있는 소명 자료이다.
+// 외부 리소스 없이 한 파일로 만들고, 해시 검증에 쓰는 JSON 원본을 함께 넣는다.
+func HTML(archive Archive, sealed Sealed) ([]byte, error) {
+	raw, err := json.Marshal(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = htmlTemplate.Execute(&buf, struct {
+		Archive Archive
+		Sealed  Sealed
+		Raw     template.JS
+	}{archive, sealed, template.JS(raw)})
+	return buf.Bytes(), err
+}
+
+var htmlTemplate = template.Must(template.New("evidence").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+	"datetime": func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04:05")
+	},
+	"ptr": func(t time.Time) *time.Time { return &t },
+	"mark": func(ok bool) string {
+		if ok {
+			return "O"
+		}
+		return "X"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<title>연차휴가 사용 촉진 소명 자료</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; margin: 24px; color: #222; }
+h1 { font-size: 20px; }
+h2 { font-size: 16px; margin-top: 32px; border-bottom: 2px solid #333; padding-bottom: 4px; }
+h3 { font-size: 14px; margin-top: 16px; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; vertical-align: top; }
+th { background: #eee; white-space: nowrap; }
+pre { white-space: pre-wrap; margin: 0; font-family: inherit; }
+.hash { font-family: monospace; word-break: break-all; }
+.grant { page-break-inside: avoid; }
+</style>
+</head>
+<body>
+<h1>연차휴가 사용 촉진 소명 자료</h1>
+<table>
+<tr><th>생성 시각</th><td>{{datetime (ptr .Archive.GeneratedAt)}}</td></tr>
+<tr><th>회사</th><td>{{.Archive.Scope.CompanyID}}</td></tr>
+{{- with .Archive.Scope.MemberID}}
+<tr><th>멤버</th><td>{{.}}</td></tr>
+{{- end}}
+{{- with .Archive.Scope.Year}}
+<tr><th>연도</th><td>{{.}}</td></tr>
+{{- end}}
+<tr><th>지급분 수</th><td>{{len .Archive.Grants}}</td></tr>
+<tr><th>해시 ({{.Sealed.HashAlgorithm}})</th><td class="hash">{{.Sealed.Hash}}</td></tr>
+</table>
+<p>해시는 이 파일에 포함된 JSON 원본(archive)으로 계산합니다. 내보낼 때 기록한 해시와 다르면 자료가 변경된 것입니다.</p>
+{{range .Archive.Grants}}
+<div class="grant">
+<h2>{{.MemberName}} ({{.MemberEmail}}) - 지급분 {{.GivenVacationID}}</h2>
+<table>
+<tr><th>지급일 / 만료일</th><td>{{date .GenerateDate}} / {{date .ExpireDate}}</td></tr>
+<tr><th>지급 / 사용 / 잔여</th><td>{{.GivenDays}}일 / {{.UsedDays}}일 / {{.RemainingDays}}일</td></tr>
+<tr><th>촉진 일정</th><td>{{.Deadlines.Track}}</td></tr>
+<tr><th>촉진 상태</th><td>{{.VacationPromotionState}}{{if .IsExpired}} (소멸){{end}}</td></tr>
+</table>
+<h3>촉진 기한</h3>
+<table>
+<tr><th>구분</th><th>기한</th><th>실제</th><th>기한 준수</th></tr>
+<tr><td>1차 촉진</td><td>{{date .Deadlines.FirstNoticeFrom}} ~ {{date .Deadlines.FirstNoticeBy}}</td><td>{{datetime .Compliance.FirstNoticeSentAt}}</td><td>{{mark .Compliance.FirstNoticeOnTime}}</td></tr>
+<tr><td>사용 시기 제출</td><td>{{date .Deadlines.ResponseBy}}까지</td><td>-</td><td>-</td></tr>
+<tr><td>2차 촉진</td><td>{{date .Deadlines.SecondNoticeBy}}까지</td><td>{{datetime .Compliance.SecondNoticeSentAt}}</td><td>{{if .Compliance.SecondNoticeSkipped}}해당 없음{{else}}{{mark .Compliance.SecondNoticeOnTime}}{{end}}</td></tr>
+</table>
+<h3>통지</h3>
+{{- if .Notices}}
+<table>
+<tr><th>알림</th><th>종류</th><th>발송</th><th>수신자</th><th>읽음</th><th>확인</th><th>내용</th></tr>
+{{- range .Notices}}{{$notice := .}}
+{{- range $i, $recipient := .Recipients}}
+<tr>
+{{- if eq $i 0}}<td rowspan="{{len $notice.Recipients}}">{{$notice.NotificationID}}</td><td rowspan="{{len $notice.Recipients}}">{{$notice.NotificationType}}</td><td rowspan="{{len $notice.Recipients}}">{{datetime (ptr $notice.SentAt)}}</td>{{end}}
+<td>{{$recipient.MemberName}}</td><td>{{datetime $recipient.ReadAt}}</td><td>{{if $recipient.Acknowledged}}{{datetime $recipient.AcknowledgedAt}}{{else}}-{{end}}</td>
+{{- if eq $i 0}}<td rowspan="{{len $notice.Recipients}}"><pre>{{$notice.Contents}}</pre></td>{{end}}
+</tr>
+{{- end}}
+{{- end}}
+</table>
+{{- else}}
+<p>통지 없음</p>
+{{- end}}
+<h3>사용 계획 / 지정 사용 시기</h3>
+{{- if .Plans}}
+<table>
+<tr><th>계획</th><th>구분</th><th>알림</th><th>제출(지정)</th><th>상태</th><th>휴가</th></tr>
+{{- range .Plans}}
+<tr><td>{{.VacationPlanID}}</td><td>{{if .Designated}}회사 지정{{else}}멤버 제출{{end}}</td><td>{{.NotificationID}}</td><td>{{datetime (ptr .ApplyDate)}}</td>
+<td>{{if .RejectState}}반려{{else if .CompleteState}}승인 완료{{else}}결재 중{{end}}</td>
+<td>{{range .Vacations}}{{date .StartDate}} ~ {{date .EndDate}}{{if .HalfFirst}} (첫날 반차){{end}}{{if .HalfLast}} (마지막날 반차){{end}}{{if .RejectState}} (반려){{end}}<br>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>사용 계획 없음</p>
+{{- end}}
+<h3>노무수령 거부 통지</h3>
+{{- if .DenyWorks}}
+<table>
+<tr><th>날짜</th><th>휴가</th><th>알림</th><th>통지</th><th>확인</th><th>메모</th></tr>
+{{- range .DenyWorks}}
+<tr><td>{{date .WorkDate}}</td><td>{{.ApplyVacationID}}</td><td>{{.NotificationID}}</td><td>{{datetime (ptr .IssuedAt)}}</td><td>{{datetime .AcceptedAt}}</td><td>{{.Memo}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>노무수령 거부 통지 없음</p>
+{{- end}}
+<h3>촉진 상태 이력</h3>
+<table>
+<tr><th>시각</th><th>이전</th><th>이후</th><th>처리자</th><th>사유</th></tr>
+{{- range .Histories}}
+<tr><td>{{datetime (ptr .CreatedAt)}}</td><td>{{.FromState}}</td><td>{{.ToState}}</td><td>{{with .ActorID}}{{.}}{{else}}자동{{end}}</td><td>{{.Reason}}</td></tr>
+{{- end}}
+</table>
+{{- if .Expiries}}
+<h3>소멸</h3>
+<table>
+<tr><th>만료일</th><th>잔여</th><th>이월</th><th>소멸</th><th>촉진 완료</th><th>사유</th></tr>
+{{- range .Expiries}}
+<tr><td>{{date .ExpireDate}}</td><td>{{.RemainingDays}}</td><td>{{.CarriedDays}}</td><td>{{.ForfeitedDays}}</td><td>{{mark .PromotionCompleted}}</td><td>{{.Reason}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</div>
+{{end}}
+<script type="application/json" id="evidence-archive">{{.Raw}}</script>
+</body>
+</html>
+`))
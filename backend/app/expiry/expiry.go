@@ -0,0 +1,241 @@
+package expiry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"cywell.com/vacation-promotion/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Company.CarryOverPolicy 값
+const (
+	PolicyNone   = "none"   // 남은 일수 전부 소멸
+	PolicyCapped = "capped" // CarryOverMaxDays 까지 이월, 나머지 소멸
+	PolicyFull   = "full"   // 전부 이월
+)
+
+func ValidPolicy(policy string) bool {
+	switch policy {
+	case "", PolicyNone, PolicyCapped, PolicyFull:
+		return true
+	}
+	return false
+}
+
+// Engine 은 소멸일이 지난 지급분을 소멸 처리하고 회사 이월 정책에 따라 남은 일수를 이월한다.
+// 처리된 지급분은 IsExpired 가 되어 건너뛰므로 여러 번 실행해도 안전하다.
+type Engine struct {
+	db  *database.Database
+	now func() time.Time
+}
+
+// NewEngine 은 now 를 기준 시각으로 사용하는 Engine 을 만든다. now 가 nil 이면 time.Now 를 사용한다.
+func NewEngine(db *database.Database, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	return &Engine{db: db, now: now}
+}
+
+// ExpireMember 는 한 멤버의 소멸일이 지난 지급분을 처리하고 처리 기록을 반환한다.
+func (e *Engine) ExpireMember(memberID uint) ([]models.VacationExpiry, error) {
+	var member models.Member
+	if err := e.db.Preload("Company").First(&member, memberID).Error; err != nil {
+		return nil, err
+	}
+
+	var expiries []models.VacationExpiry
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		expiries, err = e.expire(tx, member.Company, member)
+		return err
+	})
+	return expiries, err
+}
+
+// ExpireCompany 는 회사의 모든 멤버(퇴직자 포함)에 대해 ExpireMember 를 수행한다.
+// 실패한 멤버가 있어도 나머지 멤버는 계속 처리하고 멤버별 오류를 모아 반환한다.
+func (e *Engine) ExpireCompany(companyID uint) ([]models.VacationExpiry, error) {
+	var company models.Company
+	if err := e.db.First(&company, companyID).Error; err != nil {
+		return nil, err
+	}
+
+	var members []models.Member
+	if err := e.db.Where("company_id = ?", companyID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	expiries := make([]models.VacationExpiry, 0)
+	var errs []error
+	for _, member := range members {
+		err := e.db.Transaction(func(tx *gorm.DB) error {
+			memberExpiries, err := e.expire(tx, company, member)
+			if err != nil {
+				return err
+			}
+			expiries = append(expiries, memberExpiries...)
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("member %d: %w", member.ID, err))
+		}
+	}
+	return expiries, errors.Join(errs...)
+}
+
+// 소멸일이 지났는데 처리되지 않았거나, 처리 후 예약 해제 등으로 남은 일수가 다시 생긴 지급분을 처리한다.
+// 처리 중 만들어진 이월 지급분도 이미 소멸일이 지났을 수 있으므로 대상이 없을 때까지 반복한다.
+// 퇴직 정산 지급분은 퇴직일에 수당으로 지급하는 일수이므로 소멸 대상이 아니다.
+func (e *Engine) expire(tx *gorm.DB, company models.Company, member models.Member) ([]models.VacationExpiry, error) {
+	today := calendar.DateOnly(e.now())
+
+	expiries := make([]models.VacationExpiry, 0)
+	for {
+		var grants []models.GivenVacation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("member_id = ? AND expire_date <= ? AND (is_expired = ? OR remaining_days > ?)", member.ID, today, false, 0).
+			Where("generate_rule <> ?", accrual.RuleRetirementSettlement).
+			Order("expire_date ASC, id ASC").
+			Find(&grants).Error; err != nil {
+			return nil, err
+		}
+		if len(grants) == 0 {
+			return expiries, nil
+		}
+
+		for i := range grants {
+			expiry, err := e.expireGrant(tx, company, member, &grants[i])
+			if err != nil {
+				return nil, err
+			}
+			expiries = append(expiries, expiry)
+		}
+	}
+}
+
+func (e *Engine) expireGrant(tx *gorm.DB, company models.Company, member models.Member, grant *models.GivenVacation) (models.VacationExpiry, error) {
+	balance, err := ledger.BalanceOf(tx, grant.ID)
+	if err != nil {
+		return models.VacationExpiry{}, err
+	}
+	remaining := balance.RemainingDays()
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	policy := company.CarryOverPolicy
+	if policy == "" {
+		policy = PolicyNone
+	}
+	carried, reason := carryOverDays(company, member, grant, remaining)
+	expireDate := calendar.DateOnly(grant.ExpireDate)
+
+	expiry := models.VacationExpiry{
+		MemberID:                 grant.MemberID,
+		GivenVacationID:          grant.ID,
+		ExpireDate:               expireDate,
+		RemainingDays:            remaining,
+		CarriedDays:              carried,
+		ForfeitedDays:            remaining - carried,
+		CarryOverPolicy:          policy,
+		VacationPromotionStateID: grant.VacationPromotionStateID,
+		PromotionCompleted:       promotionCompleted(grant.VacationPromotionStateID),
+		Reason:                   reason,
+	}
+	memo := fmt.Sprintf("%s 소멸: 잔여 %.1f일 중 이월 %.1f일, 소멸 %.1f일 (%s)",
+		expireDate.Format("2006-01-02"), remaining, carried, expiry.ForfeitedDays, reason)
+
+	if carried > 0 {
+		carryOver, err := carryOverGrant(tx, grant, expireDate)
+		if err != nil {
+			return expiry, err
+		}
+		if err := ledger.CarryOver(tx, grant, carryOver, carried, memo); err != nil {
+			return expiry, err
+		}
+		expiry.CarryOverVacationID = &carryOver.ID
+	}
+
+	if err := ledger.Expire(tx, grant, memo); err != nil {
+		return expiry, err
+	}
+	if err := tx.Model(&models.GivenVacation{}).Where("id = ?", grant.ID).Update("is_expired", true).Error; err != nil {
+		return expiry, err
+	}
+	grant.IsExpired = true
+
+	if err := tx.Create(&expiry).Error; err != nil {
+		return expiry, err
+	}
+	return expiry, nil
+}
+
+// carryOverGrant 는 같은 날 소멸된 지급분들의 이월을 받을 지급분을 찾거나 만든다.
+func carryOverGrant(tx *gorm.DB, grant *models.GivenVacation, expireDate time.Time) (*models.GivenVacation, error) {
+	var carryOver models.GivenVacation
+	err := tx.Where("member_id = ? AND generate_rule = ? AND generate_date = ?", grant.MemberID, accrual.RuleCarryOver, expireDate).
+		First(&carryOver).Error
+	if err == nil {
+		return &carryOver, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	carryOver = models.GivenVacation{
+		MemberID:                 grant.MemberID,
+		VacationGenerateTypeID:   grant.VacationGenerateTypeID,
+		VacationPromotionStateID: enums.VacationPromotionStateNone,
+		Year:                     expireDate.Year(),
+		GenerateDate:             expireDate,
+		ExpireDate:               expireDate.AddDate(1, 0, 0),
+		GenerateRule:             accrual.RuleCarryOver,
+		GenerateNote:             fmt.Sprintf("%s 소멸 지급분에서 이월", expireDate.Format("2006-01-02")),
+	}
+	if err := tx.Create(&carryOver).Error; err != nil {
+		return nil, err
+	}
+	if err := webhook.EmitMember(tx, carryOver.MemberID, webhook.EventGrantCreated, webhook.GrantDataOf(carryOver)); err != nil {
+		return nil, err
+	}
+	return &carryOver, nil
+}
+
+// carryOverDays 는 남은 일수 중 이월할 일수와 그 근거를 정한다.
+// 사용자가 촉진 절차(근로기준법 제61조)를 마치지 않은 지급분은 소멸시킬 수 없으므로 회사 정책과 관계없이 전부 이월한다.
+func carryOverDays(company models.Company, member models.Member, grant *models.GivenVacation, remaining float32) (float32, string) {
+	switch {
+	case remaining <= 0:
+		return 0, "남은 일수 없음"
+	case member.RetireDate != nil && !member.RetireDate.After(grant.ExpireDate):
+		return 0, "퇴직으로 이월 없음"
+	case grant.GenerateRule == accrual.RuleCarryOver:
+		return 0, "이월분의 사용 기한 경과"
+	case company.CarryOverPolicy == PolicyFull:
+		return remaining, "전액 이월 정책"
+	case !promotionCompleted(grant.VacationPromotionStateID):
+		return remaining, "촉진 절차 미완료로 전액 이월"
+	case company.CarryOverPolicy == PolicyCapped:
+		if remaining > company.CarryOverMaxDays {
+			return company.CarryOverMaxDays, fmt.Sprintf("최대 %.1f일 이월 정책, 촉진 완료", company.CarryOverMaxDays)
+		}
+		return remaining, fmt.Sprintf("최대 %.1f일 이월 정책, 촉진 완료", company.CarryOverMaxDays)
+	default:
+		return 0, "이월 없음 정책, 촉진 완료"
+	}
+}
+
+// 2차 촉진까지 마쳐야 미사용 휴가에 대한 사용자의 보상 의무가 면제된다.
+func promotionCompleted(stateID uint) bool {
+	return stateID == enums.VacationPromotionStateSecondComplete
+}
@@ -0,0 +1,154 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openDB 는 테스트마다 새 메모리 DB 를 연다.
+func openDB(t *testing.T) *database.Database {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 연결마다 다른 메모리 DB 가 되지 않도록
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Company{}, &models.Member{}, &models.GivenVacation{}, &models.VacationLedger{}, &models.VacationExpiry{},
+		&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatal(err)
+	}
+	return &database.Database{DB: db}
+}
+
+func TestCarryOverDays(t *testing.T) {
+	expireDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	promoted := &models.GivenVacation{GenerateRule: accrual.RuleAnniversary, ExpireDate: expireDate, VacationPromotionStateID: enums.VacationPromotionStateSecondComplete}
+	notPromoted := &models.GivenVacation{GenerateRule: accrual.RuleAnniversary, ExpireDate: expireDate, VacationPromotionStateID: enums.VacationPromotionStateFirstComplete}
+	carriedOver := &models.GivenVacation{GenerateRule: accrual.RuleCarryOver, ExpireDate: expireDate, VacationPromotionStateID: enums.VacationPromotionStateNone}
+	retired := expireDate.AddDate(0, -1, 0)
+
+	none := models.Company{CarryOverPolicy: PolicyNone}
+	capped := models.Company{CarryOverPolicy: PolicyCapped, CarryOverMaxDays: 5}
+	full := models.Company{CarryOverPolicy: PolicyFull}
+
+	tests := []struct {
+		name      string
+		company   models.Company
+		member    models.Member
+		grant     *models.GivenVacation
+		remaining float32
+		want      float32
+	}{
+		{"이월 없음 정책, 촉진 완료면 전부 소멸", none, models.Member{}, promoted, 7, 0},
+		{"정책을 정하지 않으면 이월 없음", models.Company{}, models.Member{}, promoted, 7, 0},
+		{"최대 일수까지 이월", capped, models.Member{}, promoted, 7, 5},
+		{"최대 일수보다 적으면 전부 이월", capped, models.Member{}, promoted, 3, 3},
+		{"전액 이월 정책", full, models.Member{}, promoted, 7, 7},
+		{"촉진 미완료면 이월 없음 정책이어도 전부 이월", none, models.Member{}, notPromoted, 7, 7},
+		{"촉진 미완료면 최대 일수를 넘어도 전부 이월", capped, models.Member{}, notPromoted, 7, 7},
+		{"이월분은 다시 이월하지 않음", full, models.Member{}, carriedOver, 3, 0},
+		{"소멸일 전에 퇴직하면 이월 없음", full, models.Member{RetireDate: &retired}, notPromoted, 7, 0},
+		{"남은 일수 없음", full, models.Member{}, promoted, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := carryOverDays(tt.company, tt.member, tt.grant, tt.remaining)
+			if got != tt.want {
+				t.Errorf("carryOverDays() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpireMember(t *testing.T) {
+	expireDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time { return expireDate.Add(30 * time.Hour) }
+
+	tests := []struct {
+		name      string
+		company   models.Company
+		carried   float32
+		forfeited float32
+	}{
+		{"이월 없음", models.Company{CarryOverPolicy: PolicyNone}, 0, 7},
+		{"최대 일수까지 이월", models.Company{CarryOverPolicy: PolicyCapped, CarryOverMaxDays: 5}, 5, 2},
+		{"전액 이월", models.Company{CarryOverPolicy: PolicyFull}, 7, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openDB(t)
+			company := tt.company
+			company.Name = "cywell"
+			if err := db.Create(&company).Error; err != nil {
+				t.Fatal(err)
+			}
+			member := models.Member{CompanyID: company.ID, Name: "member", Email: "member@example.com", HireDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+			if err := db.Create(&member).Error; err != nil {
+				t.Fatal(err)
+			}
+			grant := models.GivenVacation{
+				MemberID:                 member.ID,
+				VacationPromotionStateID: enums.VacationPromotionStateSecondComplete,
+				Year:                     2024,
+				GivenDays:                7,
+				GenerateDate:             expireDate.AddDate(-1, 0, 0),
+				ExpireDate:               expireDate,
+				GenerateRule:             accrual.RuleAnniversary,
+			}
+			if err := db.Create(&grant).Error; err != nil {
+				t.Fatal(err)
+			}
+			if err := ledger.Grant(db.DB, &grant, "지급"); err != nil {
+				t.Fatal(err)
+			}
+
+			engine := NewEngine(db, now)
+			expiries, err := engine.ExpireMember(member.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(expiries) != 1 || expiries[0].CarriedDays != tt.carried || expiries[0].ForfeitedDays != tt.forfeited {
+				t.Fatalf("ExpireMember() = %+v, want carried %v, forfeited %v", expiries, tt.carried, tt.forfeited)
+			}
+
+			var expired models.GivenVacation
+			if err := db.First(&expired, grant.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if !expired.IsExpired || expired.RemainingDays != 0 {
+				t.Errorf("expired grant = is_expired %v, remaining %v", expired.IsExpired, expired.RemainingDays)
+			}
+
+			var carryOvers []models.GivenVacation
+			if err := db.Where("member_id = ? AND generate_rule = ?", member.ID, accrual.RuleCarryOver).Find(&carryOvers).Error; err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.carried == 0 && len(carryOvers) != 0:
+				t.Errorf("carry-over grants = %d, want none", len(carryOvers))
+			case tt.carried > 0 && (len(carryOvers) != 1 || carryOvers[0].RemainingDays != tt.carried || !carryOvers[0].ExpireDate.Equal(expireDate.AddDate(1, 0, 0))):
+				t.Errorf("carry-over grants = %+v, want %v days until %v", carryOvers, tt.carried, expireDate.AddDate(1, 0, 0))
+			}
+
+			// 다시 실행해도 처리한 지급분은 건너뛴다
+			if again, err := engine.ExpireMember(member.ID); err != nil || len(again) != 0 {
+				t.Errorf("ExpireMember() again = %d, %v", len(again), err)
+			}
+		})
+	}
+}
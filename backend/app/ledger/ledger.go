@@ -0,0 +1,526 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 휴가 잔여일수 원장.
+// 모든 잔여일수 변동은 VacationLedger 에 추가만 되고, GivenVacation 의 요약 컬럼은 원장 합계로 다시 계산된다.
+// 모든 함수는 호출자의 트랜잭션(tx) 안에서 실행되어야 한다.
+
+var (
+	ErrNoGrant  = errors.New("지급된 휴가가 없습니다")
+	ErrZeroCost = errors.New("휴가 기간에 근무일이 없습니다")
+)
+
+// Balance 는 한 지급분의 원장 합계이다.
+type Balance struct {
+	GrantedDays  float32 // 지급 + 조정
+	ReservedDays float32 // 신청 후 최종 승인 전
+	UsedDays     float32
+	ExpiredDays  float32
+	CarriedDays  float32 // 다음 지급분으로 이월된 일수
+}
+
+func (b Balance) RemainingDays() float32 {
+	return b.GrantedDays - b.ReservedDays - b.UsedDays - b.ExpiredDays - b.CarriedDays
+}
+
+// Cost 는 신청 휴가가 차감하는 일수이다. 시작일과 종료일 사이의 주말, 공휴일, 회사 휴무일을 제외한 근무일수이고 반차는 0.5일로 계산한다.
+func Cost(tx *gorm.DB, vacation models.ApplyVacation) (float32, error) {
+	var companyID uint
+	if err := tx.Model(&models.Member{}).Where("id = ?", vacation.MemberID).Select("company_id").Scan(&companyID).Error; err != nil {
+		return 0, err
+	}
+
+	cal, err := calendar.Load(tx, companyID)
+	if err != nil {
+		return 0, err
+	}
+	return cal.WorkingDays(vacation.StartDate, vacation.EndDate, vacation.HalfFirst, vacation.HalfLast), nil
+}
+
+// Grant 는 새로 생성된 지급분의 지급 기록을 남긴다.
+func Grant(tx *gorm.DB, givenVacation *models.GivenVacation, memo string) error {
+	entry := models.VacationLedger{
+		MemberID:             givenVacation.MemberID,
+		GivenVacationID:      givenVacation.ID,
+		VacationLedgerTypeID: enums.VacationLedgerTypeGrant,
+		Days:                 givenVacation.GivenDays,
+		Memo:                 memo,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+	return Recalculate(tx, givenVacation)
+}
+
+// Adjust 는 관리자가 지급분을 수동으로 늘리거나(+) 줄인다(-).
+func Adjust(tx *gorm.DB, givenVacation *models.GivenVacation, days float32, memo string) error {
+	entry := models.VacationLedger{
+		MemberID:             givenVacation.MemberID,
+		GivenVacationID:      givenVacation.ID,
+		VacationLedgerTypeID: enums.VacationLedgerTypeAdjust,
+		Days:                 days,
+		Memo:                 memo,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+	return Recalculate(tx, givenVacation)
+}
+
+// Expire 는 지급분의 남은 일수를 소멸 처리한다.
+func Expire(tx *gorm.DB, givenVacation *models.GivenVacation, memo string) error {
+	balance, err := BalanceOf(tx, givenVacation.ID)
+	if err != nil {
+		return err
+	}
+	if remaining := balance.RemainingDays(); remaining > 0 {
+		entry := models.VacationLedger{
+			MemberID:             givenVacation.MemberID,
+			GivenVacationID:      givenVacation.ID,
+			VacationLedgerTypeID: enums.VacationLedgerTypeExpire,
+			Days:                 remaining,
+			Memo:                 memo,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+	}
+	return Recalculate(tx, givenVacation)
+}
+
+// CarryOver 는 from 의 남은 일수 중 days 만큼을 이월 지급분 to 로 옮긴다. to 는 새로 생성된 지급분이어야 한다.
+func CarryOver(tx *gorm.DB, from *models.GivenVacation, to *models.GivenVacation, days float32, memo string) error {
+	entries := []models.VacationLedger{
+		{
+			MemberID:             from.MemberID,
+			GivenVacationID:      from.ID,
+			VacationLedgerTypeID: enums.VacationLedgerTypeCarryOver,
+			Days:                 days,
+			Memo:                 memo,
+		},
+		{
+			MemberID:             to.MemberID,
+			GivenVacationID:      to.ID,
+			VacationLedgerTypeID: enums.VacationLedgerTypeGrant,
+			Days:                 days,
+			Memo:                 memo,
+		},
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return err
+	}
+	if err := Recalculate(tx, from); err != nil {
+		return err
+	}
+	return Recalculate(tx, to)
+}
+
+// Reserve 는 신청 휴가 일수를 소멸일이 빠른 지급분부터 예약한다.
+// 남은 일수가 모자라면 마지막 지급분에서 초과 예약되어 잔여일수가 음수가 된다.
+func Reserve(tx *gorm.DB, vacation models.ApplyVacation, memo string) error {
+	cost, err := Cost(tx, vacation)
+	if err != nil {
+		return err
+	}
+	if cost <= 0 {
+		return nil
+	}
+
+	grants, err := lockGrants(tx, vacation)
+	if err != nil {
+		return err
+	}
+	return reserve(tx, vacation, grants, cost, memo)
+}
+
+// lockGrants 는 신청 휴가 시작일에 사용할 수 있는 멤버의 지급분을 소멸일이 빠른 순으로 잠그고 원장 잔액과 함께 가져온다.
+// 같은 멤버의 동시 신청은 여기서 차례를 기다리므로 잔액 확인과 예약 사이에 다른 예약이 끼어들지 않는다.
+func lockGrants(tx *gorm.DB, vacation models.ApplyVacation) ([]lockedGrant, error) {
+	var grants []models.GivenVacation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("member_id = ? AND is_expired = ? AND expire_date > ?", vacation.MemberID, false, calendar.DateOnly(vacation.StartDate)).
+		Order("expire_date ASC, generate_date ASC").
+		Find(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	locked := make([]lockedGrant, 0, len(grants))
+	for i := range grants {
+		balance, err := BalanceOf(tx, grants[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		locked = append(locked, lockedGrant{grant: &grants[i], remaining: balance.RemainingDays()})
+	}
+	return locked, nil
+}
+
+type lockedGrant struct {
+	grant     *models.GivenVacation
+	remaining float32 // 원장 기준 잔여일수
+}
+
+// reserve 는 잠근 지급분들에서 cost 일을 예약한다. 마지막 지급분은 잔여일수를 넘어 예약할 수 있다.
+func reserve(tx *gorm.DB, vacation models.ApplyVacation, grants []lockedGrant, cost float32, memo string) error {
+	if len(grants) == 0 {
+		return ErrNoGrant
+	}
+	for i, locked := range grants {
+		days := locked.remaining
+		if i == len(grants)-1 || days >= cost {
+			days = cost
+		}
+		if days <= 0 {
+			continue
+		}
+
+		if err := post(tx, locked.grant, vacation, enums.VacationLedgerTypeReserve, days, memo); err != nil {
+			return err
+		}
+		cost -= days
+		if cost <= 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// ReserveFromGrants 는 신청 휴가 일수를 지정한 지급분들에서만 순서대로 예약한다. (촉진 사용 계획, 지정 사용 시기)
+// 지급분들의 남은 일수 합계를 넘으면 InsufficientBalanceError 를 반환한다.
+func ReserveFromGrants(tx *gorm.DB, vacation models.ApplyVacation, grants []*models.GivenVacation, memo string) error {
+	cost, err := Cost(tx, vacation)
+	if err != nil {
+		return err
+	}
+	if cost <= 0 {
+		return ErrZeroCost
+	}
+
+	remaining := make([]float32, len(grants))
+	var available float32
+	for i, grant := range grants {
+		balance, err := BalanceOf(tx, grant.ID)
+		if err != nil {
+			return err
+		}
+		remaining[i] = balance.RemainingDays()
+		if remaining[i] > 0 {
+			available += remaining[i]
+		}
+	}
+	if cost > available {
+		return &InsufficientBalanceError{RequestedDays: cost, AvailableDays: available}
+	}
+
+	for i, grant := range grants {
+		days := remaining[i]
+		if days <= 0 {
+			continue
+		}
+		if days > cost {
+			days = cost
+		}
+		if err := post(tx, grant, vacation, enums.VacationLedgerTypeReserve, days, memo); err != nil {
+			return err
+		}
+		cost -= days
+		if cost <= 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// InsufficientBalanceError 는 신청 일수가 잔여일수와 회사의 미리 당겨쓰기 허용 일수를 넘을 때 반환된다.
+type InsufficientBalanceError struct {
+	RequestedDays float32
+	AvailableDays float32
+	AdvanceDays   float32
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("잔여 휴가가 부족합니다 (신청 %.1f일, 잔여 %.1f일, 허용 초과 %.1f일)", e.RequestedDays, e.AvailableDays, e.AdvanceDays)
+}
+
+// ReserveWithinBalance 는 멤버의 지급분을 잠근 뒤 원장 잔액을 확인하고 예약한다.
+// 잔여일수가 모자라도 advanceDays 만큼은 초과 신청을 허용한다.
+func ReserveWithinBalance(tx *gorm.DB, vacation models.ApplyVacation, advanceDays float32, memo string) error {
+	cost, err := Cost(tx, vacation)
+	if err != nil {
+		return err
+	}
+	if cost <= 0 {
+		return ErrZeroCost
+	}
+
+	grants, err := lockGrants(tx, vacation)
+	if err != nil {
+		return err
+	}
+	var available float32
+	for _, locked := range grants {
+		available += locked.remaining
+	}
+	if cost > available+advanceDays {
+		return &InsufficientBalanceError{RequestedDays: cost, AvailableDays: available, AdvanceDays: advanceDays}
+	}
+	return reserve(tx, vacation, grants, cost, memo)
+}
+
+// Available 는 date 에 사용할 수 있는 지급분들의 잔여일수 합계이다.
+func Available(tx *gorm.DB, memberID uint, date time.Time) (float32, error) {
+	var available float64
+	if err := tx.Model(&models.GivenVacation{}).
+		Where("member_id = ? AND is_expired = ? AND expire_date > ?", memberID, false, calendar.DateOnly(date)).
+		Select("COALESCE(SUM(remaining_days), 0)").
+		Scan(&available).Error; err != nil {
+		return 0, err
+	}
+	return float32(available), nil
+}
+
+// Release 는 신청 휴가의 예약분을 모두 해제한다. (반려, 취소)
+func Release(tx *gorm.DB, vacation models.ApplyVacation, memo string) error {
+	return moveByGrant(tx, vacation, func(grant *models.GivenVacation, balance Balance) error {
+		if balance.ReservedDays <= 0 {
+			return nil
+		}
+		return post(tx, grant, vacation, enums.VacationLedgerTypeRelease, balance.ReservedDays, memo)
+	})
+}
+
+// Consume 은 최종 승인된 신청 휴가의 예약분을 사용으로 전환한다.
+func Consume(tx *gorm.DB, vacation models.ApplyVacation, memo string) error {
+	return moveByGrant(tx, vacation, func(grant *models.GivenVacation, balance Balance) error {
+		if balance.ReservedDays <= 0 {
+			return nil
+		}
+		return post(tx, grant, vacation, enums.VacationLedgerTypeConsume, balance.ReservedDays, memo)
+	})
+}
+
+// Unconsume 은 최종 승인이 취소된 신청 휴가의 사용분을 다시 예약 상태로 되돌린다.
+func Unconsume(tx *gorm.DB, vacation models.ApplyVacation, memo string) error {
+	return moveByGrant(tx, vacation, func(grant *models.GivenVacation, balance Balance) error {
+		if balance.UsedDays <= 0 {
+			return nil
+		}
+		return post(tx, grant, vacation, enums.VacationLedgerTypeConsume, -balance.UsedDays, memo)
+	})
+}
+
+// Cancel 은 신청 휴가가 차감한 예약분과 사용분을 모두 되돌린다. (삭제, 수정 전 원복)
+func Cancel(tx *gorm.DB, vacation models.ApplyVacation, memo string) error {
+	if err := Unconsume(tx, vacation, memo); err != nil {
+		return err
+	}
+	return Release(tx, vacation, memo)
+}
+
+// BalanceOf 는 지급분의 원장 합계를 계산한다.
+func BalanceOf(tx *gorm.DB, givenVacationID uint) (Balance, error) {
+	balances, err := sumBy(tx, "given_vacation_id = ?", givenVacationID)
+	if err != nil {
+		return Balance{}, err
+	}
+	return balances[givenVacationID], nil
+}
+
+// Recalculate 는 원장 합계로 GivenVacation 의 요약 컬럼을 갱신한다.
+func Recalculate(tx *gorm.DB, givenVacation *models.GivenVacation) error {
+	balance, err := BalanceOf(tx, givenVacation.ID)
+	if err != nil {
+		return err
+	}
+	givenVacation.GivenDays = balance.GrantedDays
+	givenVacation.UsedDays = balance.UsedDays
+	givenVacation.ReservedDays = balance.ReservedDays
+	givenVacation.RemainingDays = balance.RemainingDays()
+	return tx.Model(&models.GivenVacation{}).Where("id = ?", givenVacation.ID).Updates(map[string]interface{}{
+		"given_days":     givenVacation.GivenDays,
+		"used_days":      givenVacation.UsedDays,
+		"reserved_days":  givenVacation.ReservedDays,
+		"remaining_days": givenVacation.RemainingDays,
+	}).Error
+}
+
+// Backfill 은 원장 도입 전에 만들어져 원장 기록이 없는 지급분에 지급 기록과 사용 기록을 남긴다.
+// 기록을 남긴 지급분은 다음 실행에서 제외되므로 마이그레이션 때마다 실행해도 된다.
+func Backfill(tx *gorm.DB) (int, error) {
+	var grants []models.GivenVacation
+	if err := tx.Where("id NOT IN (?)", tx.Model(&models.VacationLedger{}).Select("given_vacation_id")).
+		Order("id ASC").
+		Find(&grants).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range grants {
+		grant := &grants[i]
+		entries := []models.VacationLedger{{
+			MemberID:             grant.MemberID,
+			GivenVacationID:      grant.ID,
+			VacationLedgerTypeID: enums.VacationLedgerTypeGrant,
+			Days:                 grant.GivenDays,
+			Memo:                 "원장 도입 전 지급분",
+		}}
+		// 이미 사용한 일수는 예약 후 사용으로 남겨 예약 합계가 0 이 되도록 한다
+		if grant.UsedDays > 0 {
+			for _, ledgerTypeID := range []uint{enums.VacationLedgerTypeReserve, enums.VacationLedgerTypeConsume} {
+				entries = append(entries, models.VacationLedger{
+					MemberID:             grant.MemberID,
+					GivenVacationID:      grant.ID,
+					VacationLedgerTypeID: ledgerTypeID,
+					Days:                 grant.UsedDays,
+					Memo:                 "원장 도입 전 사용분",
+				})
+			}
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			return i, err
+		}
+		if err := Recalculate(tx, grant); err != nil {
+			return i, err
+		}
+	}
+	return len(grants), nil
+}
+
+// 신청 휴가가 지급분별로 차감한 합계를 구해 지급분마다 fn 을 호출한다.
+func moveByGrant(tx *gorm.DB, vacation models.ApplyVacation, fn func(*models.GivenVacation, Balance) error) error {
+	balances, err := sumBy(tx, "apply_vacation_id = ?", vacation.ID)
+	if err != nil {
+		return err
+	}
+
+	givenVacationIDs := make([]uint, 0, len(balances))
+	for givenVacationID := range balances {
+		givenVacationIDs = append(givenVacationIDs, givenVacationID)
+	}
+	sort.Slice(givenVacationIDs, func(i, j int) bool { return givenVacationIDs[i] < givenVacationIDs[j] })
+
+	for _, givenVacationID := range givenVacationIDs {
+		balance := balances[givenVacationID]
+		var grant models.GivenVacation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&grant, givenVacationID).Error; err != nil {
+			return err
+		}
+		if err := fn(&grant, balance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func post(tx *gorm.DB, grant *models.GivenVacation, vacation models.ApplyVacation, ledgerTypeID uint, days float32, memo string) error {
+	vacationID := vacation.ID
+	planID := vacation.VacationPlanID
+	entry := models.VacationLedger{
+		MemberID:             grant.MemberID,
+		GivenVacationID:      grant.ID,
+		VacationLedgerTypeID: ledgerTypeID,
+		ApplyVacationID:      &vacationID,
+		VacationPlanID:       &planID,
+		Days:                 days,
+		Memo:                 memo,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+	return Recalculate(tx, grant)
+}
+
+func sumBy(tx *gorm.DB, query string, args ...interface{}) (map[uint]Balance, error) {
+	var rows []struct {
+		GivenVacationID      uint
+		VacationLedgerTypeID uint
+		Days                 float64
+	}
+	if err := tx.Model(&models.VacationLedger{}).
+		Select("given_vacation_id, vacation_ledger_type_id, SUM(days) AS days").
+		Where(query, args...).
+		Group("given_vacation_id, vacation_ledger_type_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make(map[uint]Balance)
+	for _, row := range rows {
+		balance := balances[row.GivenVacationID]
+		days := float32(row.Days)
+		switch row.VacationLedgerTypeID {
+		case enums.VacationLedgerTypeGrant, enums.VacationLedgerTypeAdjust:
+			balance.GrantedDays += days
+		case enums.VacationLedgerTypeReserve:
+			balance.ReservedDays += days
+		case enums.VacationLedgerTypeRelease:
+			balance.ReservedDays -= days
+		case enums.VacationLedgerTypeConsume:
+			balance.ReservedDays -= days
+			balance.UsedDays += days
+		case enums.VacationLedgerTypeExpire:
+			balance.ExpiredDays += days
+		case enums.VacationLedgerTypeCarryOver:
+			balance.CarriedDays += days
+		}
+		balances[row.GivenVacationID] = balance
+	}
+	return balances, nil
+}
+
+// ReservedByPlan 은 지급분별로 아직 승인되지 않은 휴가 계획이 예약 중인 일수를 구한다. [지급분ID][계획ID]일수
+func ReservedByPlan(tx *gorm.DB, givenVacationIDs []uint) (map[uint]map[uint]float32, error) {
+	reserved := make(map[uint]map[uint]float32)
+	if len(givenVacationIDs) == 0 {
+		return reserved, nil
+	}
+
+	var rows []struct {
+		GivenVacationID      uint
+		VacationPlanID       uint
+		VacationLedgerTypeID uint
+		Days                 float64
+	}
+	if err := tx.Model(&models.VacationLedger{}).
+		Select("given_vacation_id, vacation_plan_id, vacation_ledger_type_id, SUM(days) AS days").
+		Where("given_vacation_id IN ? AND vacation_plan_id IS NOT NULL", givenVacationIDs).
+		Where("vacation_ledger_type_id IN ?", []uint{enums.VacationLedgerTypeReserve, enums.VacationLedgerTypeRelease, enums.VacationLedgerTypeConsume}).
+		Group("given_vacation_id, vacation_plan_id, vacation_ledger_type_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		days := float32(row.Days)
+		if row.VacationLedgerTypeID != enums.VacationLedgerTypeReserve {
+			days = -days
+		}
+		if reserved[row.GivenVacationID] == nil {
+			reserved[row.GivenVacationID] = make(map[uint]float32)
+		}
+		reserved[row.GivenVacationID][row.VacationPlanID] += days
+	}
+
+	for givenVacationID, plans := range reserved {
+		for planID, days := range plans {
+			if days <= 0 {
+				delete(plans, planID)
+			}
+		}
+		if len(plans) == 0 {
+			delete(reserved, givenVacationID)
+		}
+	}
+	return reserved, nil
+}
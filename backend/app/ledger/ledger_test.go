@@ -0,0 +1,334 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// openDB 는 테스트마다 새 메모리 DB 를 연다.
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 연결마다 다른 메모리 DB 가 되지 않도록
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Company{}, &models.Member{}, &models.GivenVacation{}, &models.VacationPlan{}, &models.ApplyVacation{},
+		&models.VacationLedger{}, &models.PublicHoliday{}, &models.CompanyHoliday{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seed 는 멤버 한 명과 소멸일이 빠른 순으로 givenDays 만큼의 지급분들을 만든다.
+func seed(t *testing.T, tx *gorm.DB, givenDays ...float32) (models.Member, []*models.GivenVacation) {
+	t.Helper()
+	company := models.Company{Name: "cywell"}
+	if err := tx.Create(&company).Error; err != nil {
+		t.Fatal(err)
+	}
+	member := models.Member{CompanyID: company.ID, Name: "member", Email: "member@example.com", HireDate: date(2023, 1, 2), IsActive: true}
+	if err := tx.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	grants := make([]*models.GivenVacation, 0, len(givenDays))
+	for i, days := range givenDays {
+		grant := &models.GivenVacation{
+			MemberID:     member.ID,
+			Year:         2025,
+			GivenDays:    days,
+			GenerateDate: date(2025, 1, 1),
+			ExpireDate:   date(2026, time.Month(1+i), 1),
+		}
+		if err := tx.Create(grant).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := Grant(tx, grant, "지급"); err != nil {
+			t.Fatal(err)
+		}
+		grants = append(grants, grant)
+	}
+	return member, grants
+}
+
+// apply 는 2025-06-09(월) 부터 days 근무일 동안의 신청 휴가를 만든다.
+func apply(t *testing.T, tx *gorm.DB, member models.Member, days int) models.ApplyVacation {
+	t.Helper()
+	plan := models.VacationPlan{MemberID: member.ID, ApplyDate: date(2025, 6, 1)}
+	if err := tx.Create(&plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	start := date(2025, 6, 9)
+	end := start
+	for n := 1; n < days; n++ {
+		end = end.AddDate(0, 0, 1)
+		for end.Weekday() == time.Saturday || end.Weekday() == time.Sunday {
+			end = end.AddDate(0, 0, 1)
+		}
+	}
+	vacation := models.ApplyVacation{MemberID: member.ID, VacationPlanID: plan.ID, StartDate: start, EndDate: end}
+	if err := tx.Create(&vacation).Error; err != nil {
+		t.Fatal(err)
+	}
+	return vacation
+}
+
+// summary 는 지급분의 요약 컬럼이다. 원장 합계와도 같아야 한다.
+type summary struct {
+	Given, Reserved, Used, Remaining float32
+}
+
+func checkSummaries(t *testing.T, tx *gorm.DB, grants []*models.GivenVacation, want []summary) {
+	t.Helper()
+	for i, grant := range grants {
+		var saved models.GivenVacation
+		if err := tx.First(&saved, grant.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		got := summary{saved.GivenDays, saved.ReservedDays, saved.UsedDays, saved.RemainingDays}
+		if got != want[i] {
+			t.Errorf("grant %d = %+v, want %+v", i, got, want[i])
+		}
+		balance, err := BalanceOf(tx, grant.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fromLedger := (summary{balance.GrantedDays, balance.ReservedDays, balance.UsedDays, balance.RemainingDays()}); fromLedger != got {
+			t.Errorf("grant %d ledger = %+v, summary = %+v", i, fromLedger, got)
+		}
+	}
+}
+
+func TestLedger(t *testing.T) {
+	type step func(tx *gorm.DB, vacation models.ApplyVacation, memo string) error
+
+	tests := []struct {
+		name  string
+		given []float32
+		days  int
+		steps []step
+		want  []summary
+	}{
+		{
+			name:  "소멸일이 빠른 지급분부터 예약",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve},
+			want:  []summary{{2, 2, 0, 0}, {15, 1, 0, 14}},
+		},
+		{
+			name:  "잔여가 모자라면 마지막 지급분에서 초과 예약",
+			given: []float32{1, 1},
+			days:  3,
+			steps: []step{Reserve},
+			want:  []summary{{1, 1, 0, 0}, {1, 2, 0, -1}},
+		},
+		{
+			name:  "반려하면 예약 해제",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve, Release},
+			want:  []summary{{2, 0, 0, 2}, {15, 0, 0, 15}},
+		},
+		{
+			name:  "해제는 여러 번 해도 같음",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve, Release, Release},
+			want:  []summary{{2, 0, 0, 2}, {15, 0, 0, 15}},
+		},
+		{
+			name:  "최종 승인하면 사용으로 전환",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve, Consume},
+			want:  []summary{{2, 0, 2, 0}, {15, 0, 1, 14}},
+		},
+		{
+			name:  "승인 취소하면 다시 예약",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve, Consume, Unconsume},
+			want:  []summary{{2, 2, 0, 0}, {15, 1, 0, 14}},
+		},
+		{
+			name:  "취소하면 예약과 사용 모두 원복",
+			given: []float32{2, 15},
+			days:  3,
+			steps: []step{Reserve, Consume, Cancel},
+			want:  []summary{{2, 0, 0, 2}, {15, 0, 0, 15}},
+		},
+		{
+			name:  "승인 전 취소",
+			given: []float32{15},
+			days:  2,
+			steps: []step{Reserve, Cancel},
+			want:  []summary{{15, 0, 0, 15}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := openDB(t)
+			member, grants := seed(t, tx, tt.given...)
+			vacation := apply(t, tx, member, tt.days)
+			for _, step := range tt.steps {
+				if err := step(tx, vacation, tt.name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkSummaries(t, tx, grants, tt.want)
+		})
+	}
+}
+
+func TestReserveWithoutGrant(t *testing.T) {
+	tx := openDB(t)
+	member, _ := seed(t, tx)
+	if err := Reserve(tx, apply(t, tx, member, 1), "예약"); !errors.Is(err, ErrNoGrant) {
+		t.Errorf("Reserve() = %v, want %v", err, ErrNoGrant)
+	}
+}
+
+func TestRecalculate(t *testing.T) {
+	tx := openDB(t)
+	member, grants := seed(t, tx, 15)
+	vacation := apply(t, tx, member, 3)
+	if err := Reserve(tx, vacation, "예약"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Adjust(tx, grants[0], 1, "조정"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 요약 컬럼이 어긋나도 원장 합계로 되돌린다
+	if err := tx.Model(&models.GivenVacation{}).Where("id = ?", grants[0].ID).
+		Updates(map[string]interface{}{"given_days": 0, "reserved_days": 0, "used_days": 9, "remaining_days": 0}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := Recalculate(tx, grants[0]); err != nil {
+		t.Fatal(err)
+	}
+	checkSummaries(t, tx, grants, []summary{{16, 3, 0, 13}})
+}
+
+func TestBackfill(t *testing.T) {
+	tx := openDB(t)
+	member, grants := seed(t, tx, 15)
+
+	// 원장 도입 전 지급분
+	old := models.GivenVacation{MemberID: member.ID, Year: 2024, GivenDays: 15, UsedDays: 4, RemainingDays: 11, GenerateDate: date(2024, 1, 1), ExpireDate: date(2025, 12, 1)}
+	if err := tx.Create(&old).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []int{1, 0} {
+		count, err := Backfill(tx)
+		if err != nil || count != want {
+			t.Fatalf("Backfill() = %d, %v, want %d", count, err, want)
+		}
+	}
+	checkSummaries(t, tx, append(grants, &old), []summary{{15, 0, 0, 15}, {15, 0, 4, 11}})
+}
+
+func TestReserveWithinBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		given    []float32
+		reserved int // 먼저 예약해 둔 다른 신청 일수
+		days     int
+		advance  float32
+		err      *InsufficientBalanceError
+		want     []summary
+	}{
+		{
+			name:  "잔여 안에서 예약",
+			given: []float32{2, 3},
+			days:  3,
+			want:  []summary{{2, 2, 0, 0}, {3, 1, 0, 2}},
+		},
+		{
+			name:  "잔여를 모두 예약",
+			given: []float32{2, 3},
+			days:  5,
+			want:  []summary{{2, 2, 0, 0}, {3, 3, 0, 0}},
+		},
+		{
+			name:  "잔여를 넘으면 거절",
+			given: []float32{2, 3},
+			days:  6,
+			err:   &InsufficientBalanceError{RequestedDays: 6, AvailableDays: 5},
+			want:  []summary{{2, 0, 0, 2}, {3, 0, 0, 3}},
+		},
+		{
+			name:    "미리 당겨쓰기 허용 일수까지 초과 예약",
+			given:   []float32{2, 3},
+			days:    6,
+			advance: 1,
+			want:    []summary{{2, 2, 0, 0}, {3, 4, 0, -1}},
+		},
+		{
+			name:    "허용 일수도 넘으면 거절",
+			given:   []float32{2, 3},
+			days:    7,
+			advance: 1,
+			err:     &InsufficientBalanceError{RequestedDays: 7, AvailableDays: 5, AdvanceDays: 1},
+			want:    []summary{{2, 0, 0, 2}, {3, 0, 0, 3}},
+		},
+		{
+			name:     "다른 신청이 예약한 일수는 빼고 확인",
+			given:    []float32{2, 3},
+			reserved: 2,
+			days:     4,
+			advance:  0.5,
+			err:      &InsufficientBalanceError{RequestedDays: 4, AvailableDays: 3, AdvanceDays: 0.5},
+			want:     []summary{{2, 2, 0, 0}, {3, 0, 0, 3}},
+		},
+		{
+			name:     "이미 초과 예약한 만큼 허용 일수가 줄어듦",
+			given:    []float32{2},
+			reserved: 3,
+			days:     2,
+			advance:  2,
+			err:      &InsufficientBalanceError{RequestedDays: 2, AvailableDays: -1, AdvanceDays: 2},
+			want:     []summary{{2, 3, 0, -1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := openDB(t)
+			member, grants := seed(t, tx, tt.given...)
+			if tt.reserved > 0 {
+				if err := Reserve(tx, apply(t, tx, member, tt.reserved), "먼저 신청"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := ReserveWithinBalance(tx, apply(t, tx, member, tt.days), tt.advance, tt.name)
+			var insufficient *InsufficientBalanceError
+			switch {
+			case tt.err == nil && err != nil:
+				t.Fatalf("ReserveWithinBalance() = %v", err)
+			case tt.err != nil && (!errors.As(err, &insufficient) || *insufficient != *tt.err):
+				t.Fatalf("ReserveWithinBalance() = %v, want %v", err, tt.err)
+			}
+			checkSummaries(t, tx, grants, tt.want)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// DigestData 는 여러 알림을 한 통으로 모은 묶음 메일 템플릿에 넘기는 값이다.
+type DigestData struct {
+	RecipientName string
+	Frequency     string // daily, weekly
+	Items         []TemplateData
+}
+
+// RenderDigest 는 묶음 메일 제목과 본문을 만든다. 지원하지 않는 언어면 한국어를 쓴다.
+func RenderDigest(language string, data DigestData) (subject, body string, err error) {
+	t, ok := digestTemplates[language]
+	if !ok {
+		t = digestTemplates[LanguageKorean]
+	}
+
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := t.body.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
+
+var digestTemplates = map[string]mailTemplate{
+	LanguageKorean: {
+		subject: template.Must(template.New("subject").Parse(
+			`[휴가 관리] {{if eq .Frequency "weekly"}}주간{{else}}일간{{end}} 알림 모음 ({{len .Items}}건)`)),
+		body: template.Must(template.New("body").Funcs(templateFuncs).Parse(
+			`{{.RecipientName}}님, 지난 {{if eq .Frequency "weekly"}}한 주{{else}}하루{{end}} 동안 받은 알림 {{len .Items}}건입니다.
+{{range $i, $item := .Items}}
+[{{$item.TypeName}}] {{datetime $item.SentAt}}
+{{$item.Contents}}
+{{end}}
+--
+알림 수신 설정에서 묶음 발송 주기를 바꿀 수 있습니다. 이 메일은 발신 전용입니다.
+`)),
+	},
+	LanguageEnglish: {
+		subject: template.Must(template.New("subject").Parse(
+			`[Leave Management] Your {{.Frequency}} notification digest ({{len .Items}})`)),
+		body: template.Must(template.New("body").Funcs(templateFuncs).Parse(
+			`Dear {{.RecipientName}},
+
+Here are the {{len .Items}} notifications you received during the last {{if eq .Frequency "weekly"}}week{{else}}day{{end}}.
+{{range $i, $item := .Items}}
+[{{$item.TypeName}}] {{datetime $item.SentAt}}
+{{$item.Contents}}
+{{end}}
+--
+You can change the digest frequency in your notification settings. This is an unmonitored mailbox.
+`)),
+	},
+}
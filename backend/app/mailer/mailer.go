@@ -0,0 +1,244 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config.Security 값
+const (
+	SecurityStartTLS = "starttls" // 반드시 STARTTLS 로 전환. 서버가 지원하지 않으면 보내지 않음
+	SecurityTLS      = "tls"      // 처음부터 TLS 로 접속 (보통 465 포트)
+	SecurityNone     = "none"     // 암호화하지 않음. 로컬 테스트용 SMTP 서버
+	// 지정하지 않으면 서버가 지원할 때만 STARTTLS 로 전환한다
+)
+
+const defaultTimeout = 30 * time.Second
+
+var (
+	ErrNotConfigured       = errors.New("SMTP 서버가 설정되지 않았습니다")
+	ErrStartTLSUnsupported = errors.New("SMTP 서버가 STARTTLS 를 지원하지 않습니다")
+)
+
+// Config 는 SMTP 서버 접속 정보이다.
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // 보내는 사람. "이름 <주소>" 형식 가능
+	Security string
+	Timeout  time.Duration
+}
+
+// ConfigFromEnv 는 SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS, SMTP_FROM, SMTP_SECURITY 환경 변수로 설정을 만든다.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     25,
+		Username: os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASS"),
+		From:     os.Getenv("SMTP_FROM"),
+		Security: strings.ToLower(os.Getenv("SMTP_SECURITY")),
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return config, fmt.Errorf("SMTP_PORT 가 올바르지 않습니다: %w", err)
+		}
+		config.Port = p
+	}
+	return config, config.validate()
+}
+
+// Enabled 는 SMTP 서버가 설정되어 있는지이다.
+func (c Config) Enabled() bool {
+	return c.Host != ""
+}
+
+func (c Config) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	switch c.Security {
+	case "", SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return fmt.Errorf("SMTP_SECURITY 는 starttls, tls, none 중 하나여야 합니다: %s", c.Security)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("SMTP_FROM 이 올바르지 않습니다: %w", err)
+	}
+	return nil
+}
+
+// Email 은 보낼 메일 한 통이다. 본문은 일반 텍스트이다.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender 는 메일을 보낸다.
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// SMTP 는 SMTP 서버로 메일을 보내는 Sender 이다.
+type SMTP struct {
+	config Config
+}
+
+func NewSMTP(config Config) (*SMTP, error) {
+	if !config.Enabled() {
+		return nil, ErrNotConfigured
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	return &SMTP{config: config}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, email Email) error {
+	from, err := mail.ParseAddress(s.config.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return fmt.Errorf("받는 사람 주소가 올바르지 않습니다: %w", err)
+	}
+	message, err := buildMessage(from, to, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 은 SMTP 서버에 접속하고 설정에 따라 TLS 로 전환한다. 전체 대화에 Timeout 을 건다.
+func (s *SMTP) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	tlsConfig := &tls.Config{ServerName: s.config.Host}
+
+	var conn net.Conn
+	var err error
+	if s.config.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(s.config.Timeout))
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.config.Security == "" || s.config.Security == SecurityStartTLS {
+		ok, _ := client.Extension("STARTTLS")
+		if !ok && s.config.Security == SecurityStartTLS {
+			// starttls 를 지정했으면 평문으로 내려가지 않는다
+			client.Close()
+			return nil, ErrStartTLSUnsupported
+		}
+		if ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// buildMessage 는 UTF-8 일반 텍스트 메일을 만든다. 제목과 이름은 RFC 2047, 본문은 base64 로 인코딩한다.
+func buildMessage(from, to *mail.Address, email Email, now time.Time) ([]byte, error) {
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.BEncoding.Encode("UTF-8", email.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "base64"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(email.Body, "\n", "\r\n")))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes(), nil
+}
+
+func newMessageID(fromAddress string) (string, error) {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain), nil
+}
@@ -0,0 +1,123 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP 는 STARTTLS 를 알리지 않는 평문 SMTP 서버 흉내이다. stop 은 서버를 닫고 받은 메일 수를 돌려준다.
+func fakeSMTP(t *testing.T) (addr *net.TCPAddr, stop func() int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := make(chan int, 1)
+	go func() {
+		mails := 0
+		defer func() { count <- mails }()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mails += serveSMTP(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr), func() int {
+		listener.Close()
+		return <-count
+	}
+}
+
+func serveSMTP(conn net.Conn) int {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	mails := 0
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return mails
+		}
+		switch command := strings.ToUpper(strings.Fields(line + " ")[0]); command {
+		case "EHLO":
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 8BITMIME")
+		case "MAIL", "RCPT":
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 end with .")
+			if _, err := text.ReadDotBytes(); err != nil {
+				return mails
+			}
+			mails++
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return mails
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSendSecurity(t *testing.T) {
+	tests := []struct {
+		name      string
+		security  string
+		want      error
+		delivered int
+	}{
+		{"지정하지 않으면 평문으로 보냄", "", nil, 1},
+		{"none 은 평문으로 보냄", SecurityNone, nil, 1},
+		{"starttls 를 지정했는데 서버가 지원하지 않으면 실패", SecurityStartTLS, ErrStartTLSUnsupported, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, stop := fakeSMTP(t)
+			sender, err := NewSMTP(Config{
+				Host:     addr.IP.String(),
+				Port:     addr.Port,
+				From:     "휴가 관리 <no-reply@example.com>",
+				Security: tt.security,
+				Timeout:  5 * time.Second,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = sender.Send(context.Background(), Email{To: "member@example.com", Subject: "제목", Body: "본문"})
+			delivered := stop()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Send() = %v, want %v", err, tt.want)
+			}
+			if delivered != tt.delivered {
+				t.Errorf("delivered = %d, want %d", delivered, tt.delivered)
+			}
+		})
+	}
+}
+
+func TestConfigSecurity(t *testing.T) {
+	for _, security := range []string{"", SecurityStartTLS, SecurityTLS, SecurityNone} {
+		config := Config{Host: "smtp.example.com", From: "no-reply@example.com", Security: security}
+		if err := config.validate(); err != nil {
+			t.Errorf("validate(%q) = %v", security, err)
+		}
+	}
+	config := Config{Host: "smtp.example.com", Port: 587, From: "no-reply@example.com", Security: "ssl"}
+	if err := config.validate(); err == nil {
+		t.Errorf("validate(%q) = nil, want error", config.Security)
+	}
+	if _, err := NewSMTP(Config{Port: 587}); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("NewSMTP() without host = %v, want %v", err, ErrNotConfigured)
+	}
+}
@@ -0,0 +1,242 @@
+package mailer
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	"cywell.com/vacation-promotion/app/enums"
+)
+
+// 메일 언어
+const (
+	LanguageKorean  = "ko"
+	LanguageEnglish = "en"
+)
+
+// TemplateData 는 알림 메일 템플릿에 넘기는 값이다.
+type TemplateData struct {
+	RecipientName string
+	TypeID        uint
+	TypeName      string
+	Contents      string // 알림 본문. 촉진 통지는 법정 문구 그대로이다.
+	SentAt        time.Time
+}
+
+type mailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// Render 는 알림 종류와 언어에 맞는 템플릿으로 메일 제목과 본문을 만든다.
+// 종류별 템플릿이 없으면 일반 알림 템플릿을, 지원하지 않는 언어면 한국어를 쓴다.
+func Render(language string, data TemplateData) (subject, body string, err error) {
+	byType, ok := templates[language]
+	if !ok {
+		byType = templates[LanguageKorean]
+	}
+	t, ok := byType[data.TypeID]
+	if !ok {
+		t = byType[enums.NotificationTypeNormal]
+	}
+
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := t.body.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
+
+var templateFuncs = template.FuncMap{
+	"datetime": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}
+
+func newTemplate(subject, body string) mailTemplate {
+	return mailTemplate{
+		subject: template.Must(template.New("subject").Funcs(templateFuncs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(templateFuncs).Parse(body)),
+	}
+}
+
+const koreanFooter = `
+--
+발송 일시: {{datetime .SentAt}}
+이 메일은 발신 전용입니다. 자세한 내용은 휴가 관리 시스템의 알림에서 확인해 주세요.
+`
+
+const englishFooter = `
+--
+Sent at: {{datetime .SentAt}}
+This is an unmonitored mailbox. Please check the notification in the leave management system for details.
+`
+
+// koreanLegalFooter, englishLegalFooter 는 근로기준법 제61조에 따른 촉진 통지 메일에 붙인다.
+const koreanLegalFooter = `
+--
+발송 일시: {{datetime .SentAt}}
+이 메일은 근로기준법 제61조에 따른 연차휴가 사용 촉진 관련 서면 통지입니다. 휴가 관리 시스템에서 통지를 확인해 주세요.
+`
+
+const englishLegalFooter = `
+--
+Sent at: {{datetime .SentAt}}
+This email is a written notice regarding the promotion of annual leave use under Article 61 of the Korean Labor Standards Act.
+The notice text above is the legally binding Korean original. Please acknowledge it in the leave management system.
+`
+
+var templates = map[string]map[uint]mailTemplate{
+	LanguageKorean: {
+		enums.NotificationTypeNormal: newTemplate(
+			`[휴가 관리] {{.TypeName}} 알림`,
+			`{{.RecipientName}}님, 새 알림이 있습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeVacationApplied: newTemplate(
+			`[휴가 관리] 휴가 신청 결재 요청`,
+			`{{.RecipientName}}님, 결재할 휴가 신청이 있습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeVacationFirstPromotion: newTemplate(
+			`[연차휴가 사용 촉진] 미사용 연차휴가 사용 시기 지정 요청 (1차)`,
+			`{{.RecipientName}}님, 연차휴가 사용 촉진 1차 통지를 보내드립니다.
+아래 내용을 확인하고 기한 안에 사용 시기를 정해 제출해 주세요.
+
+{{.Contents}}
+`+koreanLegalFooter),
+		enums.NotificationTypeVacationSecondPromotion: newTemplate(
+			`[연차휴가 사용 촉진] 미사용 연차휴가 사용 시기 지정 통보 (2차)`,
+			`{{.RecipientName}}님, 연차휴가 사용 촉진 2차 통지를 보내드립니다.
+회사가 지정한 사용 시기를 확인해 주세요.
+
+{{.Contents}}
+`+koreanLegalFooter),
+		enums.NotificationTypeVacationDenyWork: newTemplate(
+			`[연차휴가 사용 촉진] 노무수령 거부 통지`,
+			`{{.RecipientName}}님, 노무수령 거부 통지를 보내드립니다.
+
+{{.Contents}}
+`+koreanLegalFooter),
+		enums.NotificationTypeVacationFirstPromotionAccept: newTemplate(
+			`[휴가 관리] 1차 촉진 사용 계획 제출`,
+			`{{.RecipientName}}님, 1차 촉진 통지에 대한 사용 계획이 제출되었습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeVacationSecondPromotionAccept: newTemplate(
+			`[휴가 관리] 2차 촉진 지정 통보 확인`,
+			`{{.RecipientName}}님, 2차 촉진 지정 통보를 멤버가 확인했습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeVacationDenyWorkAccept: newTemplate(
+			`[휴가 관리] 노무수령 거부 통지 확인`,
+			`{{.RecipientName}}님, 노무수령 거부 통지를 멤버가 확인했습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeApprovalDigest: newTemplate(
+			`[휴가 관리] 결재 대기 중인 휴가 신청`,
+			`{{.RecipientName}}님, 결재를 기다리는 휴가 신청이 있습니다.
+
+{{.Contents}}
+`+koreanFooter),
+		enums.NotificationTypeApprovalReminder: newTemplate(
+			`[휴가 관리] 휴가 신청 결재 지연`,
+			`{{.RecipientName}}님, 오래 결재를 기다리는 휴가 신청이 있습니다.
+
+{{.Contents}}
+`+koreanFooter),
+	},
+	LanguageEnglish: {
+		enums.NotificationTypeNormal: newTemplate(
+			`[Leave Management] New notification`,
+			`Dear {{.RecipientName}},
+
+You have a new notification.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeVacationApplied: newTemplate(
+			`[Leave Management] Leave request awaiting your approval`,
+			`Dear {{.RecipientName}},
+
+A leave request is waiting for your approval.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeVacationFirstPromotion: newTemplate(
+			`[Annual Leave Promotion] Request to schedule your unused annual leave (1st notice)`,
+			`Dear {{.RecipientName}},
+
+This is the first notice to promote the use of your annual leave.
+Please review the notice below and submit your leave schedule before the deadline.
+
+{{.Contents}}
+`+englishLegalFooter),
+		enums.NotificationTypeVacationSecondPromotion: newTemplate(
+			`[Annual Leave Promotion] Designated dates for your unused annual leave (2nd notice)`,
+			`Dear {{.RecipientName}},
+
+This is the second notice to promote the use of your annual leave.
+Please review the leave dates designated by the company.
+
+{{.Contents}}
+`+englishLegalFooter),
+		enums.NotificationTypeVacationDenyWork: newTemplate(
+			`[Annual Leave Promotion] Notice of refusal to accept work`,
+			`Dear {{.RecipientName}},
+
+The company will not accept work on your designated annual leave day.
+
+{{.Contents}}
+`+englishLegalFooter),
+		enums.NotificationTypeVacationFirstPromotionAccept: newTemplate(
+			`[Leave Management] Leave schedule submitted for a 1st promotion notice`,
+			`Dear {{.RecipientName}},
+
+A member has submitted a leave schedule in response to a 1st promotion notice.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeVacationSecondPromotionAccept: newTemplate(
+			`[Leave Management] 2nd promotion notice acknowledged`,
+			`Dear {{.RecipientName}},
+
+A member has acknowledged the designated leave dates of a 2nd promotion notice.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeVacationDenyWorkAccept: newTemplate(
+			`[Leave Management] Refusal to accept work acknowledged`,
+			`Dear {{.RecipientName}},
+
+A member has acknowledged a notice of refusal to accept work.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeApprovalDigest: newTemplate(
+			`[Leave Management] Leave requests awaiting your approval`,
+			`Dear {{.RecipientName}},
+
+The following leave requests are waiting for approval.
+
+{{.Contents}}
+`+englishFooter),
+		enums.NotificationTypeApprovalReminder: newTemplate(
+			`[Leave Management] Overdue leave approval`,
+			`Dear {{.RecipientName}},
+
+A leave request has been waiting for approval for a while.
+
+{{.Contents}}
+`+englishFooter),
+	},
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ApprovalSetting 은 회사별 결재 대기 요약과 독촉 설정이다. 저장된 설정이 없는 회사는 기본 설정을 쓴다.
+// bool 값은 생성할 때 false 가 기본값으로 바뀌지 않도록 default 태그를 두지 않는다.
+type ApprovalSetting struct {
+	ID             uint `gorm:"primaryKey"`
+	CompanyID      uint `gorm:"uniqueIndex"`
+	DigestEnabled  bool // 결재자별 대기 요약 매일 발송 여부
+	ReminderDays   int  // 결재 차례가 된 뒤 결재자에게 다시 알릴 때까지 일수. 0 이면 독촉하지 않는다
+	EscalationDays int  // 결재 차례가 된 뒤 회사 관리자에게 알릴 때까지 일수. 0 이면 알리지 않는다
+	// 결재자 없이 제출한 촉진 사용 계획을 바로 승인할지 여부. 끄면 결재자를 한 명 이상 지정해야 한다
+	AutoApprovePromotionPlans bool
+	UpdatedAt                 time.Time
+	UpdatedBy                 *uint
+}
+
+// ApprovalReminder 는 결재 대기 요약, 독촉을 보낸 기록이다.
+// 같은 결재 단계에 독촉과 관리자 보고는 한 번만, 요약은 하루에 한 번만 보낸다.
+type ApprovalReminder struct {
+	ID              uint          `gorm:"primaryKey"`
+	ApproverOrderID uint          `gorm:"uniqueIndex:idx_approval_reminder;not null"`
+	ApproverOrder   ApproverOrder `gorm:"foreignKey:ApproverOrderID"`
+	VacationPlanID  uint          `gorm:"index;not null"`
+	Kind            string        `gorm:"size:20;uniqueIndex:idx_approval_reminder;not null"` // digest, reminder, escalation
+	SentOn          time.Time     `gorm:"type:date;uniqueIndex:idx_approval_reminder;not null"`
+	NotificationID  uint          `gorm:"index"`
+	WaitingDays     int
+	CreatedAt       time.Time
+}
@@ -8,6 +8,10 @@ type Company struct {
 	AccountingDay          time.Time            `gorm:"type:date"` // MM-DD 형식
 	VacationGenerateTypeID uint                 `gorm:"index"`
 	VacationGenerateType   VacationGenerateType `gorm:"foreignKey:VacationGenerateTypeID"`
+	PromotionSetting       *PromotionSetting    `gorm:"foreignKey:CompanyID"` // 없으면 기본 촉진 설정
+	AdvanceVacationDays    float32              `gorm:"default:0"`            // 잔여일수를 넘어 미리 당겨 쓸 수 있는 일수. 0 이상, advance-vacation API 로만 바꾼다
+	CarryOverPolicy        string               `gorm:"size:20;default:none"` // none, capped, full
+	CarryOverMaxDays       float32              `gorm:"default:0"`            // capped 정책의 최대 이월 일수
 	Admins                 []*Member            `gorm:"many2many:member_admins"`
 	Members                []*Member            `gorm:"foreignKey:CompanyID"`
 	Groups                 []*Group             `gorm:"foreignKey:CompanyID"`
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// CompanyHoliday 는 회사 자체 휴무일이다. (창립기념일, 연말 휴무 등) 휴가 일수에서 제외된다.
+type CompanyHoliday struct {
+	ID        uint      `gorm:"primaryKey"`
+	CompanyID uint      `gorm:"uniqueIndex:idx_company_holiday_date"`
+	Company   Company   `gorm:"foreignKey:CompanyID"`
+	Date      time.Time `gorm:"type:date;uniqueIndex:idx_company_holiday_date"`
+	Name      string    `gorm:"size:60"`
+}
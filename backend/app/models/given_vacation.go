@@ -15,8 +15,11 @@ type GivenVacation struct {
 	Year                     int
 	GivenDays                float32
 	GenerateDate             time.Time
-	UsedDays                 int
-	RemainingDays            int
-	ReservedDays             int
+	ExpireDate               time.Time
+	GenerateRule             string `gorm:"size:30"`
+	GenerateNote             string `gorm:"size:255"`
+	UsedDays                 float32
+	RemainingDays            float32
+	ReservedDays             float32
 	IsExpired                bool
 }
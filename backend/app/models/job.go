@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type JobRun struct {
+	ID         uint   `gorm:"primaryKey"`
+	JobName    string `gorm:"size:60;index"`
+	InstanceID string `gorm:"size:100"`
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Status     string `gorm:"size:20"`
+	Error      string `gorm:"type:text"`
+	Manual     bool
+}
+
+type JobLock struct {
+	JobName     string `gorm:"primaryKey;size:60"`
+	LockedBy    string `gorm:"size:100"`
+	LockedUntil *time.Time
+}
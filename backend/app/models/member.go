@@ -12,6 +12,7 @@ type Member struct {
 	HireDate            time.Time
 	RetireDate          *time.Time
 	IsActive            bool
+	Language            string                `gorm:"size:5;default:ko"` // 이메일 알림 언어 (ko, en)
 	Admin               []*Company            `gorm:"many2many:member_admins"`
 	GivenVacations      []GivenVacation       `gorm:"foreignKey:MemberID"`
 	ApplyVacations      []ApplyVacation       `gorm:"foreignKey:MemberID"`
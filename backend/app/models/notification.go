@@ -1,9 +1,15 @@
 package models
 
+import "time"
+
 type Notification struct {
 	ID                  uint                  `gorm:"primaryKey"`
 	NotificationTypeID  uint                  `gorm:"index"`
 	NotificationType    NotificationType      `gorm:"foreignKey:NotificationTypeID"`
 	Contents            string                `gorm:"type:text"`
+	GivenVacationID     *uint                 `gorm:"index"` // 관련 지급분 (촉진 통지 대상)
+	VacationPlanID      *uint                 `gorm:"index"` // 관련 휴가 계획
+	ApplyVacationID     *uint                 `gorm:"index"` // 관련 휴가
 	NotificationMembers []*NotificationMember `gorm:"foreignKey:NotificationID"`
+	CreatedAt           time.Time
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NotificationDelivery 는 알림을 수신자에게 외부 채널(이메일)로 보낸 상태이다.
+// 알림을 만들 때 대기 상태로 쌓고, 발송 작업이 보내면서 결과와 재시도 시각을 남긴다.
+type NotificationDelivery struct {
+	ID             uint         `gorm:"primaryKey"`
+	NotificationID uint         `gorm:"uniqueIndex:idx_delivery_recipient;not null"`
+	Notification   Notification `gorm:"foreignKey:NotificationID"`
+	MemberID       uint         `gorm:"uniqueIndex:idx_delivery_recipient;index;not null"`
+	Member         Member       `gorm:"foreignKey:MemberID"`
+	Channel        string       `gorm:"uniqueIndex:idx_delivery_recipient;size:20;not null"`
+	Address        string       `gorm:"size:100"` // 발송 시점의 수신 주소
+	Status         string       `gorm:"size:20;index;not null"`
+	Digest         string       `gorm:"size:10;not null;default:''"` // 묶음 발송 주기 (daily, weekly). 비어 있으면 바로 보낸다
+	Attempts       int
+	NextAttemptAt  *time.Time `gorm:"index"` // 대기 중일 때 다음 발송 시각
+	LastError      string     `gorm:"type:text"`
+	SentAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
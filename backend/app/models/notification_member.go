@@ -1,9 +1,13 @@
 package models
 
+import "time"
+
 type NotificationMember struct {
 	MemberID       uint         `gorm:"primaryKey"`
 	Member         Member       `gorm:"foreignKey:MemberID"`
-	NotificationID uint         `gorm:"primaryKey"`
+	NotificationID uint         `gorm:"primaryKey;index"`
 	Notification   Notification `gorm:"foreignKey:NotificationID"`
 	IsApprove      bool
+	ReadAt         *time.Time // 처음 읽은 시각
+	AcknowledgedAt *time.Time // 확인(IsApprove) 시각
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// NotificationPreference 는 멤버가 알림 종류별로 받을 채널과 이메일 묶음 발송 주기이다.
+// 행이 없으면 기본값(앱, 이메일 즉시 발송)을 쓴다.
+type NotificationPreference struct {
+	ID                 uint             `gorm:"primaryKey"`
+	MemberID           uint             `gorm:"uniqueIndex:idx_preference_member_type;not null"`
+	Member             Member           `gorm:"foreignKey:MemberID"`
+	NotificationTypeID uint             `gorm:"uniqueIndex:idx_preference_member_type;not null"`
+	NotificationType   NotificationType `gorm:"foreignKey:NotificationTypeID"`
+	InApp              bool             // 앱 알림함, 실시간 스트림
+	Email              bool
+	Webhook            bool
+	Digest             string `gorm:"size:10;not null"` // 이메일 발송 주기 (immediate, daily, weekly)
+	UpdatedAt          time.Time
+}
+
+// NotificationSetting 은 멤버의 알림 방해 금지 시간이다. 시작, 종료는 "15:04" 형식의 서버 현지 시각이고
+// 시작이 종료보다 늦으면 자정을 넘긴다. 방해 금지 시간에 생긴 이메일은 종료 시각에 보낸다.
+type NotificationSetting struct {
+	MemberID        uint    `gorm:"primaryKey"`
+	Member          Member  `gorm:"foreignKey:MemberID"`
+	QuietHoursStart *string `gorm:"size:5"`
+	QuietHoursEnd   *string `gorm:"size:5"`
+	UpdatedAt       time.Time
+}
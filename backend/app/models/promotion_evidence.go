@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PromotionEvidence 는 내보낸 촉진 소명 자료의 해시 기록이다. 제출된 자료의 변조 여부를 확인할 때 사용한다.
+type PromotionEvidence struct {
+	ID          uint   `gorm:"primaryKey"`
+	CompanyID   uint   `gorm:"index"`
+	MemberID    *uint  `gorm:"index"`
+	Year        *int   // 지급일 기준 연도
+	GrantCount  int    // 포함된 지급분 수
+	Hash        string `gorm:"size:64;index"`
+	GeneratedBy *uint  // 내보낸 멤버
+	CreatedAt   time.Time
+}
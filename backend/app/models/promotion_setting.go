@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PromotionSetting 은 회사별 연차 사용 촉진 설정이다. 저장된 설정이 없는 회사는 법정 기한대로 촉진한다.
+// bool 값은 생성할 때 false 가 기본값으로 바뀌지 않도록 default 태그를 두지 않는다.
+type PromotionSetting struct {
+	ID                     uint `gorm:"primaryKey"`
+	CompanyID              uint `gorm:"uniqueIndex"`
+	Enabled                bool // 촉진 사용 여부. 끄면 자동 진행과 촉진 통지 발송을 하지 않는다
+	FirstNoticeOffsetDays  int  // 1차 촉진 기한보다 앞당겨 보낼 일수
+	SecondNoticeOffsetDays int  // 2차 촉진 기한보다 앞당겨 보낼 일수
+	ResponseDays           int  // 사용 시기 제출 기간. 0 이거나 법정 기간보다 짧으면 법정 기간
+	AutoProposeDates       bool // 2차 촉진 사용 시기 자동 제안 여부
+	UpdatedAt              time.Time
+	UpdatedBy              *uint
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// PublicHoliday 는 법정 공휴일 외에 관리자가 추가한 공휴일이다. (선거일, 임시공휴일)
+type PublicHoliday struct {
+	ID   uint      `gorm:"primaryKey"`
+	Date time.Time `gorm:"type:date;uniqueIndex"`
+	Name string    `gorm:"size:60"`
+}
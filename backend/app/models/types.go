@@ -15,6 +15,11 @@ type VacationGenerateType struct {
 	Description string `gorm:"type:text"`
 }
 
+type VacationLedgerType struct {
+	ID       uint   `gorm:"primaryKey"`
+	TypeName string `gorm:"size:30"`
+}
+
 type NotificationType struct {
 	ID       uint   `gorm:"primaryKey"`
 	TypeName string `gorm:"size:30"`
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// VacationDenyWork 는 촉진 휴가일에 출근한 멤버에게 보낸 노무수령 거부 통지와 멤버의 확인 기록이다.
+// 미사용 연차 보상 의무 면제의 근거가 되므로 삭제하지 않는다.
+type VacationDenyWork struct {
+	ID              uint          `gorm:"primaryKey"`
+	MemberID        uint          `gorm:"index"`
+	Member          Member        `gorm:"foreignKey:MemberID"`
+	GivenVacationID uint          `gorm:"index"`
+	GivenVacation   GivenVacation `gorm:"foreignKey:GivenVacationID"`
+	ApplyVacationID uint          `gorm:"uniqueIndex:idx_deny_work_vacation_date"`
+	ApplyVacation   ApplyVacation `gorm:"foreignKey:ApplyVacationID"`
+	WorkDate        time.Time     `gorm:"type:date;uniqueIndex:idx_deny_work_vacation_date"`
+	NotificationID  uint          `gorm:"index"`
+	IssuedBy        *uint         // 통지한 관리자
+	Memo            string        `gorm:"size:255"`
+	AcceptedAt      *time.Time    // 멤버가 확인한 시각
+	CreatedAt       time.Time
+}
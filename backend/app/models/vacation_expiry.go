@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// VacationExpiry 는 지급분 소멸 처리 기록이다. 소멸, 이월 일수와 그 근거를 남겨 분쟁 시 소명 자료로 사용한다.
+type VacationExpiry struct {
+	ID                       uint          `gorm:"primaryKey"`
+	MemberID                 uint          `gorm:"index"`
+	Member                   Member        `gorm:"foreignKey:MemberID"`
+	GivenVacationID          uint          `gorm:"index"`
+	GivenVacation            GivenVacation `gorm:"foreignKey:GivenVacationID"`
+	ExpireDate               time.Time     `gorm:"type:date"`
+	RemainingDays            float32       // 소멸 시점 남은 일수
+	CarriedDays              float32
+	ForfeitedDays            float32
+	CarryOverPolicy          string `gorm:"size:20"`
+	VacationPromotionStateID uint   // 소멸 시점 촉진 상태
+	PromotionCompleted       bool
+	CarryOverVacationID      *uint  // 이월로 생성된 지급분
+	Reason                   string `gorm:"size:255"`
+	CreatedAt                time.Time
+}
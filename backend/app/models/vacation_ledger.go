@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type VacationLedger struct {
+	ID                   uint               `gorm:"primaryKey"`
+	MemberID             uint               `gorm:"index"`
+	Member               Member             `gorm:"foreignKey:MemberID"`
+	GivenVacationID      uint               `gorm:"index"`
+	GivenVacation        GivenVacation      `gorm:"foreignKey:GivenVacationID"`
+	VacationLedgerTypeID uint               `gorm:"index"`
+	VacationLedgerType   VacationLedgerType `gorm:"foreignKey:VacationLedgerTypeID"`
+	ApplyVacationID      *uint              `gorm:"index"`
+	VacationPlanID       *uint              `gorm:"index"`
+	Days                 float32
+	Memo                 string `gorm:"size:255"`
+	CreatedAt            time.Time
+}
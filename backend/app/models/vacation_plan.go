@@ -12,4 +12,5 @@ type VacationPlan struct {
 	RejectState    bool            `gorm:"not null"`
 	CompleteState  bool            `gorm:"not null"`
 	ApplyVacations []ApplyVacation `gorm:"foreignKey:VacationPlanID"`
+	NotificationID *uint           `gorm:"index"` // 촉진 통지에 대한 사용 계획이면 해당 알림
 }
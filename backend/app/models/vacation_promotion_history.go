@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// VacationPromotionHistory 는 지급분의 촉진 상태 변경 이력이다. 추가만 되고 수정되지 않는다.
+type VacationPromotionHistory struct {
+	ID              uint                   `gorm:"primaryKey"`
+	GivenVacationID uint                   `gorm:"index"`
+	GivenVacation   GivenVacation          `gorm:"foreignKey:GivenVacationID"`
+	MemberID        uint                   `gorm:"index"`
+	FromStateID     uint                   `gorm:"index"`
+	FromState       VacationPromotionState `gorm:"foreignKey:FromStateID"`
+	ToStateID       uint                   `gorm:"index"`
+	ToState         VacationPromotionState `gorm:"foreignKey:ToStateID"`
+	ActorID         *uint                  // 상태를 바꾼 멤버. 스케줄러가 바꾼 경우 nil
+	Reason          string                 `gorm:"size:255"`
+	NotificationID  *uint                  `gorm:"index"` // 변경의 근거가 된 촉진 알림. 한 알림으로 여러 지급분을 촉진할 수 있다
+	CreatedAt       time.Time              `gorm:"index"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// WebhookEndpoint 는 회사가 등록한 웹훅 수신 주소와 구독 이벤트이다.
+type WebhookEndpoint struct {
+	ID          uint    `gorm:"primaryKey"`
+	CompanyID   uint    `gorm:"index;not null"`
+	Company     Company `gorm:"foreignKey:CompanyID"`
+	URL         string  `gorm:"size:500;not null"`
+	Secret      string  `gorm:"size:100;not null"` // HMAC-SHA256 서명 키
+	Events      string  `gorm:"size:500;not null"` // 구독 이벤트. 쉼표 구분, "*" 는 전체
+	Description string  `gorm:"size:200"`
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WebhookDelivery 는 이벤트 하나를 웹훅 주소 하나로 보낸 상태이다.
+// 이벤트가 생긴 트랜잭션에서 대기 상태로 쌓고, 발송 작업이 보내면서 결과와 재시도 시각을 남긴다.
+type WebhookDelivery struct {
+	ID                uint            `gorm:"primaryKey"`
+	WebhookEndpointID uint            `gorm:"index;not null"`
+	WebhookEndpoint   WebhookEndpoint `gorm:"foreignKey:WebhookEndpointID"`
+	EventID           string          `gorm:"size:40;index;not null"`
+	EventType         string          `gorm:"size:50;not null"`
+	Payload           string          `gorm:"type:text;not null"` // 서명해서 보내는 JSON 본문
+	Status            string          `gorm:"size:20;index;not null"`
+	Attempts          int
+	NextAttemptAt     *time.Time `gorm:"index"`
+	LastStatusCode    int
+	LastError         string `gorm:"type:text"`
+	DeliveredAt       *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,293 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cywell.com/vacation-promotion/app/mailer"
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// NotificationDelivery.Channel 값
+const ChannelEmail = "email"
+
+// NotificationDelivery.Status 값
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed" // 재시도 횟수를 다 써서 더 보내지 않는다
+)
+
+// MaxEmailAttempts 는 이메일 한 통을 보내 보는 최대 횟수이다.
+const MaxEmailAttempts = 5
+
+// retryBackoff 는 n 번째 실패 뒤 다음 발송까지 기다리는 시간이다.
+var retryBackoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+var (
+	ErrDeliveryNotFound = errors.New("발송 기록을 찾을 수 없습니다")
+	ErrDeliverySent     = errors.New("이미 발송한 메일입니다")
+)
+
+// enqueueEmail 은 이메일을 받는 수신자마다 발송 대기 기록을 만든다. 실제 발송은 DeliverEmails, DeliverDigests 가 한다.
+func enqueueEmail(tx *gorm.DB, notification models.Notification, memberIDs []uint, routed map[uint]Route) error {
+	emailIDs := make([]uint, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if routed[memberID].Email {
+			emailIDs = append(emailIDs, memberID)
+		}
+	}
+	if len(emailIDs) == 0 {
+		return nil
+	}
+
+	var members []models.Member
+	if err := tx.Select("id", "email").Where("id IN ?", emailIDs).Find(&members).Error; err != nil {
+		return err
+	}
+	deliveries := make([]models.NotificationDelivery, 0, len(members))
+	for _, member := range members {
+		if member.Email == "" {
+			continue
+		}
+		route := routed[member.ID]
+		sendAt := route.EmailAt(notification.CreatedAt)
+		delivery := models.NotificationDelivery{
+			NotificationID: notification.ID,
+			MemberID:       member.ID,
+			Channel:        ChannelEmail,
+			Address:        member.Email,
+			Status:         DeliveryPending,
+			NextAttemptAt:  &sendAt,
+		}
+		if route.Digest == DigestDaily || route.Digest == DigestWeekly {
+			delivery.Digest = route.Digest
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Create(&deliveries).Error
+}
+
+// DeliveryResult 는 이메일 발송 한 번의 결과이다.
+type DeliveryResult struct {
+	Sent    int
+	Retried int // 실패해서 다시 보내려고 대기 중인 수
+	Failed  int // 재시도 횟수를 다 써서 실패로 끝난 수
+}
+
+// DeliverEmails 는 발송 시각이 된 대기 메일(묶음 발송 제외)을 최대 limit 통 보낸다.
+// 메일 서버와 통신하는 동안 트랜잭션을 잡지 않도록 한 통씩 결과를 저장한다.
+func DeliverEmails(ctx context.Context, db *gorm.DB, sender mailer.Sender, now time.Time, limit int) (DeliveryResult, error) {
+	var result DeliveryResult
+
+	var deliveries []models.NotificationDelivery
+	if err := db.Preload("Notification.NotificationType").
+		Preload("Member").
+		Where("channel = ? AND status = ? AND digest = '' AND next_attempt_at <= ?", ChannelEmail, DeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return result, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		sendErr := sendEmail(ctx, sender, delivery)
+		if err := recordAttempt(db, &delivery, sendErr, now); err != nil {
+			return result, err
+		}
+		switch delivery.Status {
+		case DeliverySent:
+			result.Sent++
+		case DeliveryFailed:
+			result.Failed++
+		default:
+			result.Retried++
+		}
+	}
+	return result, nil
+}
+
+// DeliverDigests 는 묶음 발송 시각이 된 멤버마다 대기 중인 알림을 한 통의 메일로 모아 보낸다. 최대 limit 명에게 보낸다.
+func DeliverDigests(ctx context.Context, db *gorm.DB, sender mailer.Sender, now time.Time, limit int) (DeliveryResult, error) {
+	var result DeliveryResult
+
+	var memberIDs []uint
+	if err := db.Model(&models.NotificationDelivery{}).
+		Where("channel = ? AND status = ? AND digest <> '' AND next_attempt_at <= ?", ChannelEmail, DeliveryPending, now).
+		Distinct("member_id").
+		Order("member_id ASC").
+		Limit(limit).
+		Pluck("member_id", &memberIDs).Error; err != nil {
+		return result, err
+	}
+
+	for _, memberID := range memberIDs {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		var deliveries []models.NotificationDelivery
+		if err := db.Preload("Notification.NotificationType").
+			Preload("Member").
+			Where("member_id = ? AND channel = ? AND status = ? AND digest <> '' AND next_attempt_at <= ?", memberID, ChannelEmail, DeliveryPending, now).
+			Order("notification_id ASC").
+			Find(&deliveries).Error; err != nil {
+			return result, err
+		}
+		if len(deliveries) == 0 {
+			continue
+		}
+
+		sendErr := sendDigest(ctx, sender, deliveries)
+		for i := range deliveries {
+			if err := recordAttempt(db, &deliveries[i], sendErr, now); err != nil {
+				return result, err
+			}
+		}
+		switch deliveries[0].Status {
+		case DeliverySent:
+			result.Sent++
+		case DeliveryFailed:
+			result.Failed++
+		default:
+			result.Retried++
+		}
+	}
+	return result, nil
+}
+
+func sendDigest(ctx context.Context, sender mailer.Sender, deliveries []models.NotificationDelivery) error {
+	member := deliveries[0].Member
+	data := mailer.DigestData{RecipientName: member.Name, Frequency: deliveries[0].Digest}
+	for _, delivery := range deliveries {
+		data.Items = append(data.Items, mailer.TemplateData{
+			RecipientName: member.Name,
+			TypeID:        delivery.Notification.NotificationTypeID,
+			TypeName:      delivery.Notification.NotificationType.TypeName,
+			Contents:      delivery.Notification.Contents,
+			SentAt:        delivery.Notification.CreatedAt,
+		})
+	}
+	subject, body, err := mailer.RenderDigest(member.Language, data)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, mailer.Email{To: deliveries[0].Address, Subject: subject, Body: body})
+}
+
+func sendEmail(ctx context.Context, sender mailer.Sender, delivery models.NotificationDelivery) error {
+	subject, body, err := mailer.Render(delivery.Member.Language, mailer.TemplateData{
+		RecipientName: delivery.Member.Name,
+		TypeID:        delivery.Notification.NotificationTypeID,
+		TypeName:      delivery.Notification.NotificationType.TypeName,
+		Contents:      delivery.Notification.Contents,
+		SentAt:        delivery.Notification.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, mailer.Email{To: delivery.Address, Subject: subject, Body: body})
+}
+
+// recordAttempt 는 발송 결과를 저장한다. 실패하면 재시도 간격만큼 뒤로 미루고, 횟수를 다 쓰면 실패로 끝낸다.
+func recordAttempt(db *gorm.DB, delivery *models.NotificationDelivery, sendErr error, now time.Time) error {
+	delivery.Attempts++
+	updates := map[string]interface{}{"attempts": delivery.Attempts}
+	switch {
+	case sendErr == nil:
+		delivery.Status = DeliverySent
+		delivery.SentAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		updates["sent_at"] = now
+	case delivery.Attempts >= MaxEmailAttempts:
+		delivery.Status = DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = sendErr.Error()
+	default:
+		next := now.Add(retryBackoff[min(delivery.Attempts, len(retryBackoff))-1])
+		delivery.NextAttemptAt = &next
+		delivery.LastError = sendErr.Error()
+	}
+	updates["status"] = delivery.Status
+	updates["next_attempt_at"] = delivery.NextAttemptAt
+	updates["last_error"] = delivery.LastError
+	return db.Model(&models.NotificationDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error
+}
+
+// DeliveryFilter 는 발송 기록 조회 조건이다. 비어 있는 조건은 적용하지 않는다.
+type DeliveryFilter struct {
+	Status         string
+	NotificationID uint
+	MemberID       uint
+}
+
+// DeliveryPage 는 회사 멤버에게 보낸 발송 기록 한 페이지이다.
+type DeliveryPage struct {
+	Items []models.NotificationDelivery // Member, Notification 포함
+	Page  Page
+	Total int64
+}
+
+// ListDeliveries 는 회사 멤버에게 보낸 발송 기록을 최근 것부터 조회한다.
+func ListDeliveries(tx *gorm.DB, companyID uint, filter DeliveryFilter, page Page) (DeliveryPage, error) {
+	result := DeliveryPage{Page: page.normalize(), Items: make([]models.NotificationDelivery, 0)}
+
+	query := tx.Model(&models.NotificationDelivery{}).
+		Joins("JOIN members ON members.id = notification_deliveries.member_id").
+		Where("members.company_id = ?", companyID)
+	if filter.Status != "" {
+		query = query.Where("notification_deliveries.status = ?", filter.Status)
+	}
+	if filter.NotificationID != 0 {
+		query = query.Where("notification_deliveries.notification_id = ?", filter.NotificationID)
+	}
+	if filter.MemberID != 0 {
+		query = query.Where("notification_deliveries.member_id = ?", filter.MemberID)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+	err := query.Session(&gorm.Session{}).
+		Preload("Member").
+		Preload("Notification.NotificationType").
+		Order("notification_deliveries.id DESC").
+		Offset((result.Page.Number - 1) * result.Page.Size).
+		Limit(result.Page.Size).
+		Find(&result.Items).Error
+	return result, err
+}
+
+// RetryDelivery 는 실패했거나 대기 중인 발송을 바로 다시 보내도록 하고 재시도 횟수를 새로 센다.
+func RetryDelivery(tx *gorm.DB, companyID, deliveryID uint, now time.Time) (models.NotificationDelivery, error) {
+	var delivery models.NotificationDelivery
+	if err := tx.Joins("JOIN members ON members.id = notification_deliveries.member_id").
+		Where("members.company_id = ? AND notification_deliveries.id = ?", companyID, deliveryID).
+		First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return delivery, ErrDeliveryNotFound
+		}
+		return delivery, err
+	}
+	if delivery.Status == DeliverySent {
+		return delivery, ErrDeliverySent
+	}
+
+	if err := tx.Model(&models.NotificationDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+	}).Error; err != nil {
+		return delivery, err
+	}
+	err := tx.Preload("Member").Preload("Notification.NotificationType").First(&delivery, delivery.ID).Error
+	return delivery, err
+}
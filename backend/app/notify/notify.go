@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"errors"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNotFound = errors.New("알림을 찾을 수 없습니다")
+
+// 수신함 한 페이지 크기
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Message 는 보낼 알림 내용과 알림이 가리키는 대상(지급분, 휴가 계획, 휴가)이다.
+type Message struct {
+	TypeID          uint
+	Contents        string
+	GivenVacationID *uint
+	VacationPlanID  *uint
+	ApplyVacationID *uint
+}
+
+// Send 는 알림을 하나 만들고 수신자별 수신 설정에 따라 앱 알림함(읽음, 확인 상태)에 넣고 이메일 발송을 예약한다.
+// 웹훅 수신을 설정한 수신자의 알림은 회사 웹훅으로 notification.created 이벤트를 보낸다.
+// 같은 수신자는 한 번만 받는다. 법정 촉진 알림은 수신 설정과 관계없이 앱과 이메일로 보낸다.
+func Send(tx *gorm.DB, message Message, recipientIDs []uint) (models.Notification, error) {
+	notification := models.Notification{
+		NotificationTypeID: message.TypeID,
+		Contents:           message.Contents,
+		GivenVacationID:    message.GivenVacationID,
+		VacationPlanID:     message.VacationPlanID,
+		ApplyVacationID:    message.ApplyVacationID,
+	}
+	memberIDs := make([]uint, 0, len(recipientIDs))
+	seen := make(map[uint]bool, len(recipientIDs))
+	for _, memberID := range recipientIDs {
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+		memberIDs = append(memberIDs, memberID)
+	}
+	if len(memberIDs) == 0 {
+		err := tx.Create(&notification).Error
+		return notification, err
+	}
+
+	routed, err := routes(tx, message.TypeID, memberIDs)
+	if err != nil {
+		return notification, err
+	}
+	for _, memberID := range memberIDs {
+		if routed[memberID].InApp {
+			notification.NotificationMembers = append(notification.NotificationMembers, &models.NotificationMember{MemberID: memberID})
+		}
+	}
+	if err := tx.Create(&notification).Error; err != nil {
+		return notification, err
+	}
+	for _, memberID := range memberIDs {
+		if routed[memberID].Webhook {
+			if err := webhook.EmitMember(tx, memberID, webhook.EventNotificationCreated, webhook.NotificationDataOf(notification, memberID)); err != nil {
+				return notification, err
+			}
+		}
+	}
+	return notification, enqueueEmail(tx, notification, memberIDs, routed)
+}
+
+// CompanyAdmins 는 회사 관리자 멤버 ID 이다.
+func CompanyAdmins(tx *gorm.DB, companyID uint) ([]uint, error) {
+	var adminIDs []uint
+	err := tx.Model(&models.MemberAdmin{}).
+		Where("company_id = ?", companyID).
+		Pluck("member_id", &adminIDs).Error
+	return adminIDs, err
+}
+
+// InboxFilter 는 수신함 조회 조건이다. 비어 있는 조건은 적용하지 않는다.
+type InboxFilter struct {
+	UnreadOnly         bool
+	UnacknowledgedOnly bool
+	TypeIDs            []uint
+}
+
+// Page 는 1부터 시작하는 페이지 번호와 크기이다.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// normalize 는 범위를 벗어난 페이지 번호와 크기를 기본값으로 바꾼다.
+func (p Page) normalize() Page {
+	if p.Number < 1 {
+		p.Number = 1
+	}
+	if p.Size < 1 {
+		p.Size = DefaultPageSize
+	}
+	if p.Size > MaxPageSize {
+		p.Size = MaxPageSize
+	}
+	return p
+}
+
+// Inbox 는 멤버가 받은 알림 한 페이지이다.
+type Inbox struct {
+	Items  []models.NotificationMember // Notification, Notification.NotificationType 포함
+	Page   Page
+	Total  int64 // 조건에 맞는 전체 알림 수
+	Unread int64 // 조건과 관계없이 읽지 않은 알림 수
+}
+
+// List 는 멤버 수신함을 최근 알림부터 페이지 단위로 조회한다.
+func List(tx *gorm.DB, memberID uint, filter InboxFilter, page Page) (Inbox, error) {
+	inbox := Inbox{Page: page.normalize(), Items: make([]models.NotificationMember, 0)}
+
+	query := tx.Model(&models.NotificationMember{}).
+		Joins("JOIN notifications ON notifications.id = notification_members.notification_id").
+		Where("notification_members.member_id = ?", memberID)
+	if filter.UnreadOnly {
+		query = query.Where("notification_members.read_at IS NULL")
+	}
+	if filter.UnacknowledgedOnly {
+		query = query.Where("notification_members.is_approve = ?", false)
+	}
+	if len(filter.TypeIDs) > 0 {
+		query = query.Where("notifications.notification_type_id IN ?", filter.TypeIDs)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&inbox.Total).Error; err != nil {
+		return inbox, err
+	}
+	if err := query.Session(&gorm.Session{}).
+		Preload("Notification").
+		Preload("Notification.NotificationType").
+		Order("notifications.created_at DESC, notifications.id DESC").
+		Offset((inbox.Page.Number - 1) * inbox.Page.Size).
+		Limit(inbox.Page.Size).
+		Find(&inbox.Items).Error; err != nil {
+		return inbox, err
+	}
+
+	err := tx.Model(&models.NotificationMember{}).
+		Where("member_id = ? AND read_at IS NULL", memberID).
+		Count(&inbox.Unread).Error
+	return inbox, err
+}
+
+// Get 은 멤버가 받은 알림 하나와 수신 상태이다.
+func Get(tx *gorm.DB, memberID, notificationID uint) (models.NotificationMember, error) {
+	var recipient models.NotificationMember
+	if err := tx.Preload("Notification").
+		Preload("Notification.NotificationType").
+		Where("member_id = ? AND notification_id = ?", memberID, notificationID).
+		First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return recipient, ErrNotFound
+		}
+		return recipient, err
+	}
+	return recipient, nil
+}
+
+// MarkRead 는 멤버가 알림을 읽었다고 표시한다. 이미 읽은 알림은 처음 읽은 시각을 유지한다.
+func MarkRead(tx *gorm.DB, memberID, notificationID uint, now time.Time) (models.NotificationMember, error) {
+	result := tx.Model(&models.NotificationMember{}).
+		Where("member_id = ? AND notification_id = ? AND read_at IS NULL", memberID, notificationID).
+		Update("read_at", now)
+	if result.Error != nil {
+		return models.NotificationMember{}, result.Error
+	}
+	return Get(tx, memberID, notificationID)
+}
+
+// MarkAllRead 는 멤버가 읽지 않은 알림을 모두 읽음으로 표시하고 바뀐 수를 반환한다.
+func MarkAllRead(tx *gorm.DB, memberID uint, now time.Time) (int64, error) {
+	result := tx.Model(&models.NotificationMember{}).
+		Where("member_id = ? AND read_at IS NULL", memberID).
+		Update("read_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// Acknowledge 는 멤버가 알림을 확인했다고 표시한다. 읽지 않은 알림이면 읽은 시각도 함께 남기고,
+// 이미 확인한 알림은 처음 확인한 시각을 유지한다.
+func Acknowledge(tx *gorm.DB, memberID, notificationID uint, now time.Time) (models.NotificationMember, error) {
+	var recipient models.NotificationMember
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("member_id = ? AND notification_id = ?", memberID, notificationID).
+		First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return recipient, ErrNotFound
+		}
+		return recipient, err
+	}
+	if !recipient.IsApprove {
+		if err := tx.Model(&models.NotificationMember{}).
+			Where("member_id = ? AND notification_id = ?", memberID, notificationID).
+			Updates(map[string]interface{}{
+				"is_approve":      true,
+				"acknowledged_at": now,
+				"read_at":         gorm.Expr("COALESCE(read_at, ?)", now),
+			}).Error; err != nil {
+			return recipient, err
+		}
+	}
+	return Get(tx, memberID, notificationID)
+}
@@ -0,0 +1,236 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreference.Digest 값
+const (
+	DigestImmediate = "immediate"
+	DigestDaily     = "daily"  // 매일 digestHour 에 모아 보낸다
+	DigestWeekly    = "weekly" // 매주 월요일 digestHour 에 모아 보낸다
+)
+
+// digestHour 는 묶음 메일을 보내는 시각(서버 현지 시각)이다.
+const digestHour = 9
+
+var ErrInvalidQuietHours = errors.New("방해 금지 시간은 HH:MM 형식으로 시작과 종료를 함께 지정해야 합니다")
+
+// mandatoryTypes 는 근로기준법 제61조에 따라 서면으로 통지해야 하는 촉진 알림이다.
+// 수신 거부, 묶음 발송, 방해 금지 시간과 관계없이 앱과 이메일로 바로 보낸다. 웹훅은 설정을 따른다.
+var mandatoryTypes = map[uint]bool{
+	enums.NotificationTypeVacationFirstPromotion:  true,
+	enums.NotificationTypeVacationSecondPromotion: true,
+	enums.NotificationTypeVacationDenyWork:        true,
+}
+
+// Mandatory 는 수신 설정을 무시하고 보내야 하는 법정 촉진 알림인지이다.
+func Mandatory(typeID uint) bool {
+	return mandatoryTypes[typeID]
+}
+
+// DefaultPreference 는 설정하지 않은 알림 종류의 수신 설정이다.
+func DefaultPreference(memberID, typeID uint) models.NotificationPreference {
+	return models.NotificationPreference{
+		MemberID:           memberID,
+		NotificationTypeID: typeID,
+		InApp:              true,
+		Email:              true,
+		Digest:             DigestImmediate,
+	}
+}
+
+// QuietHours 는 하루 중 방해 금지 시간이다. 분 단위이며 Start > End 면 자정을 넘긴다.
+type QuietHours struct {
+	Enabled bool
+	Start   int
+	End     int
+}
+
+// ParseQuietHours 는 "15:04" 형식의 시작, 종료 시각을 읽는다. 둘 다 없으면 방해 금지 시간이 없다.
+func ParseQuietHours(start, end *string) (QuietHours, error) {
+	if start == nil && end == nil {
+		return QuietHours{}, nil
+	}
+	if start == nil || end == nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	s, err := time.Parse("15:04", *start)
+	if err != nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	e, err := time.Parse("15:04", *end)
+	if err != nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	q := QuietHours{Start: s.Hour()*60 + s.Minute(), End: e.Hour()*60 + e.Minute()}
+	q.Enabled = q.Start != q.End
+	return q, nil
+}
+
+// Defer 는 t 가 방해 금지 시간이면 끝나는 시각을, 아니면 t 를 그대로 돌려준다.
+func (q QuietHours) Defer(t time.Time) time.Time {
+	if !q.Enabled {
+		return t
+	}
+	minute := t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch {
+	case q.Start < q.End && minute >= q.Start && minute < q.End:
+		return midnight.Add(time.Duration(q.End) * time.Minute)
+	case q.Start > q.End && minute >= q.Start:
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(q.End) * time.Minute)
+	case q.Start > q.End && minute < q.End:
+		return midnight.Add(time.Duration(q.End) * time.Minute)
+	}
+	return t
+}
+
+// nextDigest 는 t 이후 처음 돌아오는 묶음 메일 발송 시각이다.
+func nextDigest(digest string, t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), digestHour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if digest == DigestWeekly {
+		for next.Weekday() != time.Monday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// Route 는 알림 하나를 수신자에게 보낼 채널이다.
+type Route struct {
+	InApp   bool
+	Email   bool
+	Webhook bool
+	Digest  string
+	Quiet   QuietHours
+}
+
+// EmailAt 은 이메일을 보낼 시각이다. 묶음 발송이면 다음 묶음 발송 시각, 아니면 방해 금지 시간이 끝난 뒤이다.
+func (r Route) EmailAt(now time.Time) time.Time {
+	if r.Digest == DigestDaily || r.Digest == DigestWeekly {
+		return nextDigest(r.Digest, now)
+	}
+	return r.Quiet.Defer(now)
+}
+
+// routes 는 수신자별 수신 설정으로 알림을 보낼 채널을 정한다. 법정 촉진 알림은 앱, 이메일 설정을 무시한다.
+func routes(tx *gorm.DB, typeID uint, memberIDs []uint) (map[uint]Route, error) {
+	result := make(map[uint]Route, len(memberIDs))
+	var preferences []models.NotificationPreference
+	if err := tx.Where("notification_type_id = ? AND member_id IN ?", typeID, memberIDs).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	byMember := make(map[uint]models.NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		byMember[preference.MemberID] = preference
+	}
+	var settings []models.NotificationSetting
+	if err := tx.Where("member_id IN ?", memberIDs).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	quiet := make(map[uint]QuietHours, len(settings))
+	for _, setting := range settings {
+		q, err := ParseQuietHours(setting.QuietHoursStart, setting.QuietHoursEnd)
+		if err != nil {
+			return nil, fmt.Errorf("멤버 %d: %w", setting.MemberID, err)
+		}
+		quiet[setting.MemberID] = q
+	}
+
+	for _, memberID := range memberIDs {
+		preference, ok := byMember[memberID]
+		if !ok {
+			preference = DefaultPreference(memberID, typeID)
+		}
+		result[memberID] = routeOf(typeID, preference, quiet[memberID])
+	}
+	return result, nil
+}
+
+// routeOf 는 수신 설정 하나로 채널을 정한다. 법정 촉진 알림은 수신 거부, 묶음 발송, 방해 금지 시간을 무시한다.
+func routeOf(typeID uint, preference models.NotificationPreference, quiet QuietHours) Route {
+	if Mandatory(typeID) {
+		return Route{InApp: true, Email: true, Webhook: preference.Webhook, Digest: DigestImmediate}
+	}
+	return Route{
+		InApp:   preference.InApp,
+		Email:   preference.Email,
+		Webhook: preference.Webhook,
+		Digest:  preference.Digest,
+		Quiet:   quiet,
+	}
+}
+
+// Preferences 는 멤버의 방해 금지 시간과 모든 알림 종류의 수신 설정이다. 설정하지 않은 종류는 기본값이다.
+type Preferences struct {
+	Setting models.NotificationSetting
+	Items   []models.NotificationPreference // NotificationType 포함, 알림 종류 순
+}
+
+func LoadPreferences(tx *gorm.DB, memberID uint) (Preferences, error) {
+	result := Preferences{Setting: models.NotificationSetting{MemberID: memberID}}
+	if err := tx.Where("member_id = ?", memberID).Limit(1).Find(&result.Setting).Error; err != nil {
+		return result, err
+	}
+
+	var types []models.NotificationType
+	if err := tx.Order("id ASC").Find(&types).Error; err != nil {
+		return result, err
+	}
+	var saved []models.NotificationPreference
+	if err := tx.Where("member_id = ?", memberID).Find(&saved).Error; err != nil {
+		return result, err
+	}
+	byType := make(map[uint]models.NotificationPreference, len(saved))
+	for _, preference := range saved {
+		byType[preference.NotificationTypeID] = preference
+	}
+
+	for _, t := range types {
+		preference, ok := byType[t.ID]
+		if !ok {
+			preference = DefaultPreference(memberID, t.ID)
+		}
+		preference.NotificationType = t
+		result.Items = append(result.Items, preference)
+	}
+	return result, nil
+}
+
+// SavePreferences 는 방해 금지 시간과 주어진 알림 종류의 수신 설정을 저장한다. 주지 않은 종류는 그대로 둔다.
+// 법정 촉진 알림의 수신 거부도 저장은 하지만 보낼 때는 무시한다.
+func SavePreferences(tx *gorm.DB, memberID uint, setting models.NotificationSetting, preferences []models.NotificationPreference) error {
+	if _, err := ParseQuietHours(setting.QuietHoursStart, setting.QuietHoursEnd); err != nil {
+		return err
+	}
+	setting.MemberID = memberID
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "member_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quiet_hours_start", "quiet_hours_end", "updated_at"}),
+	}).Create(&setting).Error; err != nil {
+		return err
+	}
+
+	if len(preferences) == 0 {
+		return nil
+	}
+	for i := range preferences {
+		preferences[i].ID = 0
+		preferences[i].MemberID = memberID
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "member_id"}, {Name: "notification_type_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "email", "webhook", "digest", "updated_at"}),
+	}).Create(&preferences).Error
+}
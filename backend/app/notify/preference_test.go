@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+)
+
+func TestRouteOf(t *testing.T) {
+	start, end := "22:00", "07:00"
+	quiet, err := ParseQuietHours(&start, &end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	optOut := models.NotificationPreference{InApp: false, Email: false, Webhook: true, Digest: DigestDaily}
+	now := time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		typeID     uint
+		preference models.NotificationPreference
+		quiet      QuietHours
+		want       Route
+		emailAt    time.Time
+	}{
+		{
+			name:       "기본 설정은 앱, 이메일 바로 발송",
+			typeID:     enums.NotificationTypeVacationApplied,
+			preference: DefaultPreference(1, enums.NotificationTypeVacationApplied),
+			want:       Route{InApp: true, Email: true, Digest: DigestImmediate},
+			emailAt:    now,
+		},
+		{
+			name:       "수신 거부와 묶음 발송을 따름",
+			typeID:     enums.NotificationTypeVacationApplied,
+			preference: optOut,
+			quiet:      quiet,
+			want:       Route{Webhook: true, Digest: DigestDaily, Quiet: quiet},
+			emailAt:    time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "방해 금지 시간이 끝난 뒤 이메일 발송",
+			typeID:     enums.NotificationTypeApprovalReminder,
+			preference: DefaultPreference(1, enums.NotificationTypeApprovalReminder),
+			quiet:      quiet,
+			want:       Route{InApp: true, Email: true, Digest: DigestImmediate, Quiet: quiet},
+			emailAt:    time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "1차 촉진은 수신 거부와 방해 금지 시간을 무시",
+			typeID:     enums.NotificationTypeVacationFirstPromotion,
+			preference: optOut,
+			quiet:      quiet,
+			want:       Route{InApp: true, Email: true, Webhook: true, Digest: DigestImmediate},
+			emailAt:    now,
+		},
+		{
+			name:       "2차 촉진은 수신 거부와 방해 금지 시간을 무시",
+			typeID:     enums.NotificationTypeVacationSecondPromotion,
+			preference: optOut,
+			quiet:      quiet,
+			want:       Route{InApp: true, Email: true, Webhook: true, Digest: DigestImmediate},
+			emailAt:    now,
+		},
+		{
+			name:       "노무수령 거부 통지는 웹훅만 설정을 따름",
+			typeID:     enums.NotificationTypeVacationDenyWork,
+			preference: models.NotificationPreference{Digest: DigestWeekly},
+			quiet:      quiet,
+			want:       Route{InApp: true, Email: true, Digest: DigestImmediate},
+			emailAt:    now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := routeOf(tt.typeID, tt.preference, tt.quiet)
+			if got != tt.want {
+				t.Errorf("routeOf() = %+v, want %+v", got, tt.want)
+			}
+			if emailAt := got.EmailAt(now); !emailAt.Equal(tt.emailAt) {
+				t.Errorf("EmailAt() = %v, want %v", emailAt, tt.emailAt)
+			}
+		})
+	}
+}
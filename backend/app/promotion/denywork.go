@@ -0,0 +1,178 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrVacationNotFound     = errors.New("휴가를 찾을 수 없습니다")
+	ErrNotPromotionVacation = errors.New("승인 완료된 촉진 휴가가 아닙니다")
+	ErrNotVacationDay       = errors.New("휴가 기간 중의 근무일이 아닙니다")
+	ErrDenyWorkExists       = errors.New("이미 노무수령 거부 통지를 보낸 날짜입니다")
+	ErrDenyWorkAccepted     = errors.New("이미 확인한 노무수령 거부 통지입니다")
+)
+
+// IssueDenyWork 는 촉진 휴가일에 출근한 멤버에게 노무수령 거부 통지를 보내고 지급분 촉진 이력에 남긴다.
+// 촉진 상태는 바꾸지 않는다.
+func (e *Engine) IssueDenyWork(companyID, applyVacationID uint, workDate time.Time, memo string, actorID *uint) (models.VacationDenyWork, error) {
+	var denyWork models.VacationDenyWork
+	workDate = calendar.DateOnly(workDate)
+
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var vacation models.ApplyVacation
+		if err := tx.Preload("Member").Preload("VacationPlan").First(&vacation, applyVacationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVacationNotFound
+			}
+			return err
+		}
+		if vacation.Member.CompanyID != companyID {
+			return ErrVacationNotFound
+		}
+		if vacation.VacationTypeID != enums.VacationTypePromotion || vacation.RejectState ||
+			vacation.VacationPlan.RejectState || !vacation.VacationPlan.CompleteState {
+			return ErrNotPromotionVacation
+		}
+
+		cal, err := calendar.Load(tx, companyID)
+		if err != nil {
+			return err
+		}
+		if workDate.Before(calendar.DateOnly(vacation.StartDate)) || workDate.After(calendar.DateOnly(vacation.EndDate)) || !cal.IsWorkingDay(workDate) {
+			return ErrNotVacationDay
+		}
+
+		grant, err := grantOf(tx, vacation)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.VacationDenyWork{}).
+			Where("apply_vacation_id = ? AND work_date = ?", vacation.ID, workDate).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDenyWorkExists
+		}
+
+		notification, err := notify.Send(tx, notify.Message{
+			TypeID:          enums.NotificationTypeVacationDenyWork,
+			Contents:        denyWorkContents(vacation.Member, grant, workDate),
+			GivenVacationID: &grant.ID,
+			VacationPlanID:  &vacation.VacationPlanID,
+			ApplyVacationID: &vacation.ID,
+		}, []uint{vacation.MemberID})
+		if err != nil {
+			return err
+		}
+
+		denyWork = models.VacationDenyWork{
+			MemberID:        vacation.MemberID,
+			GivenVacationID: grant.ID,
+			ApplyVacationID: vacation.ID,
+			WorkDate:        workDate,
+			NotificationID:  notification.ID,
+			IssuedBy:        actorID,
+			Memo:            memo,
+		}
+		if err := tx.Create(&denyWork).Error; err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("노무수령 거부 통지 (알림 %d, 휴가 %d, %s)", notification.ID, vacation.ID, workDate.Format("2006-01-02"))
+		_, err = Record(tx, &grant, reason, actorID, &notification.ID)
+		return err
+	})
+	return denyWork, err
+}
+
+// AcceptDenyWork 는 멤버가 노무수령 거부 통지를 확인한 것으로 처리하고 회사 관리자에게 알린다.
+func (e *Engine) AcceptDenyWork(memberID, notificationID uint) (models.VacationDenyWork, error) {
+	var denyWork models.VacationDenyWork
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		notice, err := findNotice(tx, memberID, notificationID, enums.NotificationTypeVacationDenyWork)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("notification_id = ? AND member_id = ?", notice.ID, memberID).
+			First(&denyWork).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoticeNotFound
+			}
+			return err
+		}
+		if denyWork.AcceptedAt != nil {
+			return ErrDenyWorkAccepted
+		}
+
+		now := e.now()
+		denyWork.AcceptedAt = &now
+		if err := tx.Model(&denyWork).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+		if err := acknowledge(tx, memberID, notice.ID, now); err != nil {
+			return err
+		}
+
+		var grant models.GivenVacation
+		if err := tx.Preload("Member").First(&grant, denyWork.GivenVacationID).Error; err != nil {
+			return err
+		}
+		if err := notifyAdmins(tx, &grant, notify.Message{
+			TypeID:          enums.NotificationTypeVacationDenyWorkAccept,
+			Contents:        fmt.Sprintf("%s님이 %s 노무수령 거부 통지를 확인했습니다.", grant.Member.Name, denyWork.WorkDate.Format("2006-01-02")),
+			ApplyVacationID: &denyWork.ApplyVacationID,
+		}); err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("노무수령 거부 통지 확인 (알림 %d, %s)", notice.ID, denyWork.WorkDate.Format("2006-01-02"))
+		_, err = Record(tx, &grant, reason, &memberID, &notice.ID)
+		return err
+	})
+	return denyWork, err
+}
+
+// grantOf 는 휴가 일수를 예약한 지급분이다.
+func grantOf(tx *gorm.DB, vacation models.ApplyVacation) (models.GivenVacation, error) {
+	var grantIDs []uint
+	if err := tx.Model(&models.VacationLedger{}).
+		Where("apply_vacation_id = ?", vacation.ID).
+		Order("id ASC").
+		Limit(1).
+		Pluck("given_vacation_id", &grantIDs).Error; err != nil {
+		return models.GivenVacation{}, err
+	}
+	if len(grantIDs) == 0 {
+		return models.GivenVacation{}, ErrNotPromotionVacation
+	}
+
+	var grant models.GivenVacation
+	err := tx.Preload("Member").First(&grant, grantIDs[0]).Error
+	return grant, err
+}
+
+func denyWorkContents(member models.Member, grant models.GivenVacation, workDate time.Time) string {
+	return fmt.Sprintf(
+		"[노무수령 거부 통지]\n"+
+			"%s님의 %s은 연차휴가 사용 촉진에 따라 지정된 연차휴가일입니다.\n"+
+			"회사는 해당일의 근로 제공을 받지 않으므로 출근하더라도 근로를 제공하지 말고 연차휴가를 사용해 주시기 바랍니다.\n"+
+			"해당일에 근로를 제공하더라도 회사의 지시가 없는 한 근로로 인정되지 않으며, 사용하지 않은 연차휴가는 %s에 소멸하고 보상되지 않습니다.",
+		member.Name,
+		workDate.Format("2006-01-02"),
+		calendar.DateOnly(grant.ExpireDate).Format("2006-01-02"),
+	)
+}
@@ -0,0 +1,461 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"cywell.com/vacation-promotion/app/webhook"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDesignationShort      = errors.New("지정한 사용 시기가 미사용 일수보다 적습니다")
+	ErrNotAwaitingAcceptance = errors.New("사용 시기 지정 통보를 확인할 수 있는 촉진 상태가 아닙니다")
+)
+
+// Designation 은 발송 전 미리보기이거나 발송된 2차 촉진(사용 시기 지정) 통보이다.
+// 1차 통지와 같이 지급분 묶음마다 하나이며 GivenVacation 은 그중 첫 지급분이다.
+type Designation struct {
+	GivenVacation  models.GivenVacation
+	Grants         []*models.GivenVacation
+	Member         models.Member
+	UnusedDays     float32
+	Deadlines      Deadlines
+	Vacations      []PlannedVacation
+	DesignatedDays float32
+	Contents       string
+	NotificationID uint   // 발송 전이면 0
+	PlanID         uint   // 발송 전이면 0
+	Skipped        string // 보내지 못한 이유. 보냈거나 미리보기면 빈 값
+}
+
+// SecondNoticeCandidates 는 2차 촉진이 필요한 지급분 묶음마다 회사가 지정할 사용 시기를 제안한다.
+// 제안 날짜는 주말, 공휴일, 회사 휴무일, 본인 휴가를 피하고 같은 조직의 휴가가 적은 날을 먼저 고른다.
+// 자동 제안을 끈 회사는 날짜 없이 미리보기만 만든다.
+func (e *Engine) SecondNoticeCandidates(companyID uint, filter NoticeFilter) ([]Designation, error) {
+	return e.secondNoticeCandidates(e.db.DB, companyID, filter, nil)
+}
+
+// SendSecondNotices 는 대상 지급분 묶음마다 사용 시기를 지정해 촉진 휴가로 등록하고 2차 촉진 알림을 보낸 뒤 SecondNoti 로 바꾼다.
+// designated 에 묶음의 지급분 중 하나의 ID 로 날짜가 있으면 그 날짜로, 없으면 제안 날짜로 지정한다.
+// 자동 제안을 끈 회사는 designated 에 날짜가 있는 묶음만 보낼 수 있다.
+// 지정한 일수가 미사용 일수보다 적거나 날짜가 사용 기간 밖인 묶음은 보내지 않고 Skipped 에 이유를 남기며,
+// 나머지 묶음은 그대로 보낸다. 한 멤버의 문제로 다른 멤버의 법정 기한을 놓치지 않도록 하기 위해서이다.
+func (e *Engine) SendSecondNotices(companyID uint, filter NoticeFilter, designated map[uint][]PlannedVacation, actorID *uint) ([]Designation, error) {
+	var designations []Designation
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		designations, err = e.secondNoticeCandidates(tx, companyID, filter, designated)
+		if err != nil {
+			return err
+		}
+		for i := range designations {
+			designation := &designations[i]
+			if len(designation.Vacations) == 0 {
+				designation.Skipped = fmt.Sprintf("%s (지급분 %d: 지정한 사용 시기 없음)", ErrDesignationShort, designation.GivenVacation.ID)
+				continue
+			}
+			// 묶음마다 저장점을 두어 보내지 못한 묶음만 되돌린다
+			err := tx.Transaction(func(gtx *gorm.DB) error {
+				return e.sendSecondNotice(gtx, designation, actorID)
+			})
+			if err == nil {
+				continue
+			}
+			if !skippable(err) {
+				return err
+			}
+			designation.Skipped = err.Error()
+			designation.NotificationID = 0
+			designation.PlanID = 0
+		}
+		return nil
+	})
+	return designations, err
+}
+
+// skippable 은 묶음 하나만 건너뛰면 되는 오류인지 확인한다.
+func skippable(err error) bool {
+	var insufficient *ledger.InsufficientBalanceError
+	return errors.Is(err, ErrDesignationShort) || errors.Is(err, ErrOutsideUsePeriod) || errors.As(err, &insufficient)
+}
+
+// AcceptSecondNotice 는 멤버가 2차 촉진(사용 시기 지정) 통보를 확인한 것으로 처리하고 통보한 지급분을 SecondComplete 로 바꾼다.
+func (e *Engine) AcceptSecondNotice(memberID, notificationID uint) ([]models.VacationPromotionHistory, error) {
+	var histories []models.VacationPromotionHistory
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		notice, err := findNotice(tx, memberID, notificationID, enums.NotificationTypeVacationSecondPromotion)
+		if err != nil {
+			return err
+		}
+
+		grants, err := noticeGrants(tx, notice, enums.VacationPromotionStateSecondNoti)
+		if err != nil {
+			return err
+		}
+		if len(grants) == 0 {
+			return ErrNotAwaitingAcceptance
+		}
+
+		if err := acknowledge(tx, memberID, notice.ID, e.now()); err != nil {
+			return err
+		}
+		if err := notifyAdmins(tx, grants[0], notify.Message{
+			TypeID:         enums.NotificationTypeVacationSecondPromotionAccept,
+			Contents:       fmt.Sprintf("%s님이 2차 촉진 사용 시기 지정 통보를 확인했습니다.", grants[0].Member.Name),
+			VacationPlanID: notice.VacationPlanID,
+		}); err != nil {
+			return err
+		}
+
+		for _, grant := range grants {
+			history, err := transition(tx, grant, enums.VacationPromotionStateSecondComplete,
+				fmt.Sprintf("사용 시기 지정 통보 확인 (알림 %d)", notice.ID), &memberID, &notice.ID)
+			if err != nil {
+				return err
+			}
+			histories = append(histories, *history)
+		}
+		return nil
+	})
+	return histories, err
+}
+
+func (e *Engine) secondNoticeCandidates(tx *gorm.DB, companyID uint, filter NoticeFilter, designated map[uint][]PlannedVacation) ([]Designation, error) {
+	today := calendar.DateOnly(e.now())
+
+	setting, err := LoadSetting(tx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return nil, ErrPromotionDisabled
+	}
+
+	query := tx.Model(&models.GivenVacation{}).
+		Joins("JOIN members ON members.id = given_vacations.member_id").
+		Where("members.company_id = ? AND members.is_active = ?", companyID, true).
+		Where("given_vacations.is_expired = ? AND given_vacations.expire_date > ?", false, today).
+		Where("given_vacations.vacation_promotion_state_id = ? AND given_vacations.remaining_days > ?", enums.VacationPromotionStateSecondNeed, 0).
+		Preload("Member").
+		Order("given_vacations.expire_date ASC, given_vacations.member_id ASC")
+	if len(filter.OrganizeIDs) > 0 {
+		query = query.Where("members.organize_id IN ?", filter.OrganizeIDs)
+	}
+	if len(filter.GivenVacationIDs) > 0 {
+		query = query.Where("given_vacations.id IN ?", filter.GivenVacationIDs)
+	}
+
+	var grants []models.GivenVacation
+	if err := query.Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return []Designation{}, nil
+	}
+
+	cal, err := calendar.Load(tx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	proposer := newProposer(cal, today)
+
+	applicable := make([]*models.GivenVacation, 0, len(grants))
+	for i := range grants {
+		if Applicable(grants[i]) {
+			applicable = append(applicable, &grants[i])
+		}
+	}
+
+	designations := make([]Designation, 0, len(applicable))
+	for _, group := range groupGrants(applicable) {
+		grant := *group[0]
+		firstNotifiedAt, err := FirstNotifiedAt(tx, grant.ID)
+		if err != nil {
+			return nil, err
+		}
+		deadlines := DeadlinesOf(grant, firstNotifiedAt, setting)
+		unused := unusedDays(group)
+
+		var vacations []PlannedVacation
+		ok := false
+		for _, candidate := range group {
+			if vacations, ok = designated[candidate.ID]; ok {
+				break
+			}
+		}
+		if !ok && setting.AutoProposeDates {
+			if vacations, err = proposer.propose(tx, grant.Member, unused, deadlines); err != nil {
+				return nil, err
+			}
+		}
+		proposer.book(grant.Member, vacations)
+
+		designation := Designation{
+			GivenVacation: grant,
+			Grants:        group,
+			Member:        grant.Member,
+			UnusedDays:    unused,
+			Deadlines:     deadlines,
+			Vacations:     vacations,
+		}
+		for _, vacation := range vacations {
+			designation.DesignatedDays += cal.WorkingDays(vacation.StartDate, vacation.EndDate, vacation.HalfFirst, vacation.HalfLast)
+		}
+		designation.Contents = secondNoticeContents(designation)
+		designations = append(designations, designation)
+	}
+	return designations, nil
+}
+
+// sendSecondNotice 는 지정한 날짜로 승인 완료된 촉진 휴가 계획을 만들고 2차 촉진 알림을 보낸다.
+func (e *Engine) sendSecondNotice(tx *gorm.DB, designation *Designation, actorID *uint) error {
+	grant := &designation.GivenVacation
+	grants := designation.Grants
+	today := calendar.DateOnly(e.now())
+	for _, vacation := range designation.Vacations {
+		if vacation.EndDate.Before(vacation.StartDate) || calendar.DateOnly(vacation.StartDate).Before(today) || !calendar.DateOnly(vacation.EndDate).Before(designation.Deadlines.ExpireDate) {
+			return fmt.Errorf("%w (지급분 %d)", ErrOutsideUsePeriod, grant.ID)
+		}
+	}
+
+	notification, err := notify.Send(tx, notify.Message{
+		TypeID:          enums.NotificationTypeVacationSecondPromotion,
+		Contents:        designation.Contents,
+		GivenVacationID: &grant.ID,
+	}, []uint{grant.MemberID})
+	if err != nil {
+		return err
+	}
+	designation.NotificationID = notification.ID
+
+	// 회사가 지정한 사용 시기이므로 결재 없이 승인 완료로 등록한다
+	plan := models.VacationPlan{
+		MemberID:       grant.MemberID,
+		ApplyDate:      e.now(),
+		CompleteState:  true,
+		NotificationID: &notification.ID,
+	}
+	if err := tx.Create(&plan).Error; err != nil {
+		return err
+	}
+	designation.PlanID = plan.ID
+	if err := tx.Model(&notification).Update("vacation_plan_id", plan.ID).Error; err != nil {
+		return err
+	}
+
+	for _, vacation := range designation.Vacations {
+		applyVacation := models.ApplyVacation{
+			VacationPlanID: plan.ID,
+			MemberID:       grant.MemberID,
+			StartDate:      vacation.StartDate,
+			EndDate:        vacation.EndDate,
+			HalfFirst:      vacation.HalfFirst,
+			HalfLast:       vacation.HalfLast,
+			VacationTypeID: enums.VacationTypePromotion,
+		}
+		if err := tx.Create(&applyVacation).Error; err != nil {
+			return err
+		}
+		if err := ledger.ReserveFromGrants(tx, applyVacation, grants, "2차 촉진 사용 시기 지정"); err != nil {
+			return err
+		}
+		if err := ledger.Consume(tx, applyVacation, "2차 촉진 사용 시기 지정"); err != nil {
+			return err
+		}
+		plan.ApplyVacations = append(plan.ApplyVacations, applyVacation)
+	}
+	if err := webhook.EmitMember(tx, grant.MemberID, webhook.EventPlanCreated, webhook.PlanDataOf(plan)); err != nil {
+		return err
+	}
+	if unusedDays(grants) > 0 {
+		return fmt.Errorf("%w (지급분 %d: 지정 %.1f일, 미사용 %.1f일)", ErrDesignationShort, grant.ID, designation.DesignatedDays, designation.UnusedDays)
+	}
+
+	reason := fmt.Sprintf("2차 촉진 사용 시기 지정 통보 (알림 %d, 계획 %d, %.1f일)", notification.ID, plan.ID, designation.DesignatedDays)
+	for _, grant := range grants {
+		if _, err := transition(tx, grant, enums.VacationPromotionStateSecondNoti, reason, actorID, &notification.ID); err != nil {
+			return err
+		}
+	}
+	designation.GivenVacation = *grants[0]
+	return nil
+}
+
+// proposer 는 여러 지급분의 사용 시기를 차례로 제안한다.
+// 앞에서 제안한 날짜도 본인, 같은 조직의 휴가로 보고 피한다.
+type proposer struct {
+	cal      *calendar.Calendar
+	today    time.Time
+	members  map[uint]map[string]bool // 멤버별 제안한 날짜
+	organize map[uint]map[string]int  // 조직별 날짜별 제안한 휴가 수
+}
+
+func newProposer(cal *calendar.Calendar, today time.Time) *proposer {
+	return &proposer{
+		cal:      cal,
+		today:    today,
+		members:  make(map[uint]map[string]bool),
+		organize: make(map[uint]map[string]int),
+	}
+}
+
+// propose 는 2차 촉진 기한부터 만료 전날까지의 근무일 중 미사용 일수(unused)만큼 고른다.
+// 그 기간에 근무일이 부족하면 다음 날부터 고른다. 소수점 일수는 마지막 날을 반차로 한다.
+func (p *proposer) propose(tx *gorm.DB, member models.Member, unused float32, deadlines Deadlines) ([]PlannedVacation, error) {
+	from := p.today.AddDate(0, 0, 1)
+	if deadlines.SecondNoticeBy.After(from) {
+		from = deadlines.SecondNoticeBy
+	}
+	to := deadlines.ExpireDate.AddDate(0, 0, -1)
+	need := int(math.Ceil(float64(unused)))
+
+	own, team, err := p.booked(tx, member, p.today.AddDate(0, 0, 1), to)
+	if err != nil {
+		return nil, err
+	}
+
+	days := p.freeDays(from, to, own)
+	if len(days) < need {
+		days = p.freeDays(p.today.AddDate(0, 0, 1), to, own)
+	}
+	// 같은 조직 휴가가 적은 날, 이른 날 순
+	sort.SliceStable(days, func(i, j int) bool { return team[dateKey(days[i])] < team[dateKey(days[j])] })
+	if len(days) > need {
+		days = days[:need]
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	vacations := make([]PlannedVacation, 0, len(days))
+	half := float64(unused) != math.Floor(float64(unused))
+	for i, day := range days {
+		if half && i == len(days)-1 {
+			vacations = append(vacations, PlannedVacation{StartDate: day, EndDate: day, HalfFirst: true})
+			continue
+		}
+		// 근무일로 이어지는 날은 한 기간으로 묶는다
+		if n := len(vacations); n > 0 && !vacations[n-1].HalfFirst && p.nextWorkingDay(vacations[n-1].EndDate).Equal(day) {
+			vacations[n-1].EndDate = day
+			continue
+		}
+		vacations = append(vacations, PlannedVacation{StartDate: day, EndDate: day})
+	}
+	return vacations, nil
+}
+
+// book 은 제안하거나 지정한 날짜를 이후 제안에서 피하도록 기록한다.
+func (p *proposer) book(member models.Member, vacations []PlannedVacation) {
+	if p.members[member.ID] == nil {
+		p.members[member.ID] = make(map[string]bool)
+	}
+	for _, vacation := range vacations {
+		for day := calendar.DateOnly(vacation.StartDate); !day.After(calendar.DateOnly(vacation.EndDate)); day = day.AddDate(0, 0, 1) {
+			p.members[member.ID][dateKey(day)] = true
+			if member.OrganizeID != nil {
+				if p.organize[*member.OrganizeID] == nil {
+					p.organize[*member.OrganizeID] = make(map[string]int)
+				}
+				p.organize[*member.OrganizeID][dateKey(day)]++
+			}
+		}
+	}
+}
+
+// booked 는 from 부터 to 까지 본인이 휴가인 날짜와 같은 조직의 날짜별 휴가 인원을 반환한다. 반려된 휴가는 제외한다.
+func (p *proposer) booked(tx *gorm.DB, member models.Member, from, to time.Time) (map[string]bool, map[string]int, error) {
+	query := tx.Model(&models.ApplyVacation{}).
+		Joins("JOIN vacation_plans ON vacation_plans.id = apply_vacations.vacation_plan_id").
+		Where("vacation_plans.reject_state = ? AND apply_vacations.reject_state = ?", false, false).
+		Where("apply_vacations.start_date <= ? AND apply_vacations.end_date >= ?", to.AddDate(0, 0, 1), from)
+	if member.OrganizeID != nil {
+		query = query.Where("(apply_vacations.member_id = ? OR apply_vacations.member_id IN (?))", member.ID,
+			tx.Model(&models.Member{}).Select("id").Where("organize_id = ? AND is_active = ?", *member.OrganizeID, true))
+	} else {
+		query = query.Where("apply_vacations.member_id = ?", member.ID)
+	}
+
+	var vacations []models.ApplyVacation
+	if err := query.Find(&vacations).Error; err != nil {
+		return nil, nil, err
+	}
+
+	own := make(map[string]bool)
+	team := make(map[string]int)
+	for key := range p.members[member.ID] {
+		own[key] = true
+	}
+	if member.OrganizeID != nil {
+		for key, count := range p.organize[*member.OrganizeID] {
+			team[key] += count
+		}
+	}
+	for _, vacation := range vacations {
+		for day := calendar.DateOnly(vacation.StartDate); !day.After(calendar.DateOnly(vacation.EndDate)); day = day.AddDate(0, 0, 1) {
+			if vacation.MemberID == member.ID {
+				own[dateKey(day)] = true
+			} else {
+				team[dateKey(day)]++
+			}
+		}
+	}
+	return own, team, nil
+}
+
+func (p *proposer) freeDays(from, to time.Time, own map[string]bool) []time.Time {
+	days := make([]time.Time, 0)
+	for day := calendar.DateOnly(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if p.cal.IsWorkingDay(day) && !own[dateKey(day)] {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (p *proposer) nextWorkingDay(date time.Time) time.Time {
+	day := calendar.DateOnly(date).AddDate(0, 0, 1)
+	for !p.cal.IsWorkingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+func secondNoticeContents(designation Designation) string {
+	periods := make([]string, 0, len(designation.Vacations))
+	for _, vacation := range designation.Vacations {
+		period := vacation.StartDate.Format("2006-01-02")
+		if !calendar.DateOnly(vacation.EndDate).Equal(calendar.DateOnly(vacation.StartDate)) {
+			period += " ~ " + vacation.EndDate.Format("2006-01-02")
+		}
+		if vacation.HalfFirst || vacation.HalfLast {
+			period += " (반차 포함)"
+		}
+		periods = append(periods, "- "+period)
+	}
+
+	label := trackLabels[designation.Deadlines.Track]
+	return fmt.Sprintf(
+		"[연차휴가 사용 촉진 2차 통지%s]\n"+
+			"%s님이 사용 시기를 정하여 통보하지 않은 미사용 연차휴가 %.1f일의 사용 시기를 %s에 따라 다음과 같이 지정합니다.\n"+
+			"%s\n"+
+			"지정된 날짜에 연차휴가를 사용하지 않으면 미사용 연차휴가는 %s에 소멸하며 보상되지 않습니다.",
+		headingSuffix(label.name),
+		designation.Member.Name,
+		designation.UnusedDays,
+		label.article,
+		strings.Join(periods, "\n"),
+		designation.Deadlines.ExpireDate.Format("2006-01-02"),
+	)
+}
+
+func dateKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,191 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Engine 은 촉진 기한에 따라 지급분의 촉진 상태를 진행시킨다.
+// 기한이 지나지 않았거나 조건이 맞지 않으면 상태를 바꾸지 않으므로 여러 번 실행해도 안전하다.
+type Engine struct {
+	db  *database.Database
+	now func() time.Time
+}
+
+// NewEngine 은 now 를 기준 시각으로 사용하는 Engine 을 만든다. now 가 nil 이면 time.Now 를 사용한다.
+func NewEngine(db *database.Database, now func() time.Time) *Engine {
+	if now == nil {
+		now = time.Now
+	}
+	return &Engine{db: db, now: now}
+}
+
+// AdvanceCompany 는 회사의 모든 재직 멤버의 지급분 촉진 상태를 진행시키고 새로 생긴 이력을 반환한다.
+// 촉진을 사용하지 않는 회사는 아무것도 바꾸지 않는다. 실패한 멤버가 있어도 나머지 멤버는 계속 처리하고 오류를 모아 반환한다.
+func (e *Engine) AdvanceCompany(companyID uint) ([]models.VacationPromotionHistory, error) {
+	setting, err := LoadSetting(e.db.DB, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return []models.VacationPromotionHistory{}, nil
+	}
+
+	var memberIDs []uint
+	if err := e.db.Model(&models.Member{}).Where("company_id = ? AND is_active = ?", companyID, true).Pluck("id", &memberIDs).Error; err != nil {
+		return nil, err
+	}
+
+	histories := make([]models.VacationPromotionHistory, 0)
+	var errs []error
+	for _, memberID := range memberIDs {
+		memberHistories, err := e.advanceMember(memberID, setting)
+		histories = append(histories, memberHistories...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("member %d: %w", memberID, err))
+		}
+	}
+	return histories, errors.Join(errs...)
+}
+
+// AdvanceMember 는 한 멤버의 촉진 대상 지급분 상태를 진행시킨다.
+func (e *Engine) AdvanceMember(memberID uint) ([]models.VacationPromotionHistory, error) {
+	var member models.Member
+	if err := e.db.First(&member, memberID).Error; err != nil {
+		return nil, err
+	}
+	setting, err := LoadSetting(e.db.DB, member.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return []models.VacationPromotionHistory{}, nil
+	}
+	return e.advanceMember(memberID, setting)
+}
+
+func (e *Engine) advanceMember(memberID uint, setting models.PromotionSetting) ([]models.VacationPromotionHistory, error) {
+	today := calendar.DateOnly(e.now())
+
+	histories := make([]models.VacationPromotionHistory, 0)
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var grants []models.GivenVacation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Member").
+			Where("member_id = ? AND is_expired = ? AND expire_date > ? AND vacation_promotion_state_id <> ?",
+				memberID, false, today, enums.VacationPromotionStateSecondComplete).
+			Order("expire_date ASC, id ASC").
+			Find(&grants).Error; err != nil {
+			return err
+		}
+
+		applicable := make([]*models.GivenVacation, 0, len(grants))
+		for i := range grants {
+			if Applicable(grants[i]) {
+				applicable = append(applicable, &grants[i])
+			}
+		}
+		for _, group := range groupGrants(applicable) {
+			for _, grant := range group {
+				grantHistories, err := e.advance(tx, group, grant, today, setting)
+				if err != nil {
+					return err
+				}
+				histories = append(histories, grantHistories...)
+			}
+		}
+		return nil
+	})
+	return histories, err
+}
+
+// advance 는 더 이상 바뀌지 않을 때까지 한 지급분의 상태를 진행시킨다.
+// 1차 촉진을 자동으로 보낼 때는 같은 묶음(group)에서 아직 촉진하지 않은 지급분도 함께 통지한다.
+func (e *Engine) advance(tx *gorm.DB, group []*models.GivenVacation, grant *models.GivenVacation, today time.Time, setting models.PromotionSetting) ([]models.VacationPromotionHistory, error) {
+	histories := make([]models.VacationPromotionHistory, 0)
+	// 상태 수만큼만 반복해 잘못된 전이로 인한 무한 반복을 막는다
+	for i := 0; i < len(transitions); i++ {
+		next, reason, err := e.nextState(tx, grant, today, setting)
+		if err != nil {
+			return histories, err
+		}
+		if next == 0 {
+			break
+		}
+		if next == enums.VacationPromotionStateFirstNoti {
+			notice := newFirstNotice(unnotified(group), e.now(), setting)
+			sent, err := sendFirstNotice(tx, &notice, reason, nil)
+			histories = append(histories, sent...)
+			if err != nil {
+				return histories, err
+			}
+			continue
+		}
+		history, err := Transition(tx, grant, next, reason, nil)
+		if err != nil {
+			return histories, err
+		}
+		histories = append(histories, *history)
+	}
+	return histories, nil
+}
+
+// unnotified 는 묶음에서 미사용 일수가 있고 아직 1차 촉진하지 않은 지급분이다.
+func unnotified(group []*models.GivenVacation) []*models.GivenVacation {
+	grants := make([]*models.GivenVacation, 0, len(group))
+	for _, grant := range group {
+		if grant.VacationPromotionStateID == enums.VacationPromotionStateNone && grant.RemainingDays > 0 {
+			grants = append(grants, grant)
+		}
+	}
+	return grants
+}
+
+// nextState 는 기한과 남은 일수로 다음 상태를 정한다. 바꿀 필요가 없으면 0 을 반환한다.
+// 남은 일수는 신청(예약), 사용한 일수를 뺀 일수이므로 사용 계획이 제출된 일수는 포함되지 않는다.
+func (e *Engine) nextState(tx *gorm.DB, grant *models.GivenVacation, today time.Time, setting models.PromotionSetting) (uint, string, error) {
+	firstNotifiedAt, err := FirstNotifiedAt(tx, grant.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	deadlines := DeadlinesOf(*grant, firstNotifiedAt, setting)
+	remaining := grant.RemainingDays
+
+	switch grant.VacationPromotionStateID {
+	case enums.VacationPromotionStateNone:
+		// 1차 촉진은 관리자가 기간 중에 보내고, 회사의 1차 촉진 기한까지 보내지 않은 지급분만 자동으로 보낸다
+		if remaining > 0 && !today.Before(deadlines.FirstNoticeDue) {
+			reason := fmt.Sprintf("1차 촉진 기한 %s 도래로 자동 발송", deadlines.FirstNoticeDue.Format("2006-01-02"))
+			if today.After(deadlines.FirstNoticeDue) {
+				reason = fmt.Sprintf("1차 촉진 기한 %s 경과 후 자동 발송", deadlines.FirstNoticeDue.Format("2006-01-02"))
+			}
+			return enums.VacationPromotionStateFirstNoti, reason, nil
+		}
+	case enums.VacationPromotionStateFirstNoti:
+		if remaining <= 0 {
+			return enums.VacationPromotionStateFirstComplete, "미사용 일수 전부 사용 계획 제출", nil
+		}
+		if today.After(deadlines.ResponseBy) {
+			return enums.VacationPromotionStateSecondNeed,
+				fmt.Sprintf("사용 시기 제출 기한 %s 경과 (미제출 %.1f일)", deadlines.ResponseBy.Format("2006-01-02"), remaining), nil
+		}
+	case enums.VacationPromotionStateFirstComplete:
+		// 제출한 계획이 취소, 반려되어 남은 일수가 다시 생긴 경우
+		if remaining > 0 && today.After(deadlines.ResponseBy) {
+			return enums.VacationPromotionStateSecondNeed, fmt.Sprintf("제출한 사용 계획 취소로 미사용 %.1f일 발생", remaining), nil
+		}
+	case enums.VacationPromotionStateSecondNeed:
+		if remaining <= 0 {
+			return enums.VacationPromotionStateSecondComplete, "2차 촉진 전 미사용 일수 전부 사용 계획", nil
+		}
+	}
+	return 0, "", nil
+}
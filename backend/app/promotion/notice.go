@@ -0,0 +1,183 @@
+package promotion
+
+import (
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"gorm.io/gorm"
+)
+
+// Notice 는 발송 전 미리보기이거나 발송된 촉진 통지이다.
+// 같은 멤버, 촉진 일정, 만료일의 지급분은 한 통지로 보내고 GivenVacation 은 그중 첫 지급분이다.
+type Notice struct {
+	GivenVacation  models.GivenVacation
+	Grants         []*models.GivenVacation
+	Member         models.Member
+	UnusedDays     float32
+	Deadlines      Deadlines
+	Contents       string
+	NotificationID uint // 발송 전이면 0
+}
+
+// NoticeFilter 는 1차 촉진 대상 범위이다. 비어 있는 조건은 적용하지 않는다.
+type NoticeFilter struct {
+	OrganizeIDs      []uint
+	GivenVacationIDs []uint
+}
+
+// FirstNoticeCandidates 는 1차 촉진 기간이 시작되었고 미사용 일수가 있는, 아직 촉진하지 않은 지급분의 통지 미리보기이다.
+// 촉진을 사용하지 않는 회사면 ErrPromotionDisabled 를 반환한다.
+func (e *Engine) FirstNoticeCandidates(companyID uint, filter NoticeFilter) ([]Notice, error) {
+	return e.firstNoticeCandidates(e.db.DB, companyID, filter)
+}
+
+// SendFirstNotices 는 대상 지급분 묶음마다 1차 촉진 알림을 만들어 멤버에게 보내고 지급분을 FirstNoti 로 바꾼다.
+func (e *Engine) SendFirstNotices(companyID uint, filter NoticeFilter, actorID *uint) ([]Notice, error) {
+	var notices []Notice
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		notices, err = e.firstNoticeCandidates(tx, companyID, filter)
+		if err != nil {
+			return err
+		}
+		for i := range notices {
+			if _, err := sendFirstNotice(tx, &notices[i], "1차 촉진 통지 발송", actorID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return notices, err
+}
+
+func (e *Engine) firstNoticeCandidates(tx *gorm.DB, companyID uint, filter NoticeFilter) ([]Notice, error) {
+	now := e.now()
+	today := calendar.DateOnly(now)
+
+	setting, err := LoadSetting(tx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return nil, ErrPromotionDisabled
+	}
+
+	query := tx.Model(&models.GivenVacation{}).
+		Joins("JOIN members ON members.id = given_vacations.member_id").
+		Where("members.company_id = ? AND members.is_active = ?", companyID, true).
+		Where("given_vacations.is_expired = ? AND given_vacations.expire_date > ?", false, today).
+		Where("given_vacations.vacation_promotion_state_id = ? AND given_vacations.remaining_days > ?", enums.VacationPromotionStateNone, 0).
+		Preload("Member").
+		Order("given_vacations.expire_date ASC, given_vacations.member_id ASC")
+	if len(filter.OrganizeIDs) > 0 {
+		query = query.Where("members.organize_id IN ?", filter.OrganizeIDs)
+	}
+	if len(filter.GivenVacationIDs) > 0 {
+		query = query.Where("given_vacations.id IN ?", filter.GivenVacationIDs)
+	}
+
+	var grants []models.GivenVacation
+	if err := query.Find(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	due := make([]*models.GivenVacation, 0, len(grants))
+	for i := range grants {
+		if Applicable(grants[i]) {
+			due = append(due, &grants[i])
+		}
+	}
+
+	notices := make([]Notice, 0, len(due))
+	for _, group := range groupGrants(due) {
+		notice := newFirstNotice(group, now, setting)
+		if today.Before(notice.Deadlines.FirstNoticeFrom) {
+			continue
+		}
+		notices = append(notices, notice)
+	}
+	return notices, nil
+}
+
+// newFirstNotice 는 now 에 보낸다고 가정한 지급분 묶음의 1차 촉진 통지를 만든다.
+func newFirstNotice(grants []*models.GivenVacation, now time.Time, setting models.PromotionSetting) Notice {
+	grant := *grants[0]
+	deadlines := DeadlinesOf(grant, &now, setting)
+	notice := Notice{
+		GivenVacation: grant,
+		Grants:        grants,
+		Member:        grant.Member,
+		UnusedDays:    unusedDays(grants),
+		Deadlines:     deadlines,
+	}
+	notice.Contents = firstNoticeContents(notice)
+	return notice
+}
+
+// sendFirstNotice 는 1차 촉진 알림을 만들고 묶음의 지급분을 모두 FirstNoti 로 바꾼다.
+func sendFirstNotice(tx *gorm.DB, notice *Notice, reason string, actorID *uint) ([]models.VacationPromotionHistory, error) {
+	grantID := notice.GivenVacation.ID
+	notification, err := notify.Send(tx, notify.Message{
+		TypeID:          enums.NotificationTypeVacationFirstPromotion,
+		Contents:        notice.Contents,
+		GivenVacationID: &grantID,
+	}, []uint{notice.GivenVacation.MemberID})
+	if err != nil {
+		return nil, err
+	}
+	notice.NotificationID = notification.ID
+
+	reason = fmt.Sprintf("%s (알림 %d, 미사용 %.1f일, 제출 기한 %s)",
+		reason, notification.ID, notice.UnusedDays, notice.Deadlines.ResponseBy.Format("2006-01-02"))
+	histories := make([]models.VacationPromotionHistory, 0, len(notice.Grants))
+	for _, grant := range notice.Grants {
+		history, err := transition(tx, grant, enums.VacationPromotionStateFirstNoti, reason, actorID, &notification.ID)
+		if err != nil {
+			return histories, err
+		}
+		histories = append(histories, *history)
+	}
+	notice.GivenVacation = *notice.Grants[0]
+	return histories, nil
+}
+
+func firstNoticeContents(notice Notice) string {
+	deadlines := notice.Deadlines
+	label := trackLabels[deadlines.Track]
+	return fmt.Sprintf(
+		"[연차휴가 사용 촉진 1차 통지%s]\n"+
+			"%s님의 연차휴가(사용 기간 %s ~ %s) 중 미사용 일수는 %.1f일입니다.\n"+
+			"%s에 따라 %s까지 미사용 연차휴가의 사용 시기를 정하여 회사에 통보해 주시기 바랍니다.\n"+
+			"기한까지 통보하지 않으면 회사가 사용 시기를 지정하여 통보합니다.",
+		headingSuffix(label.name),
+		notice.Member.Name,
+		usePeriodFrom(notice.Grants).Format("2006-01-02"),
+		deadlines.ExpireDate.AddDate(0, 0, -1).Format("2006-01-02"),
+		notice.UnusedDays,
+		label.article,
+		deadlines.ResponseBy.Format("2006-01-02"),
+	)
+}
+
+// usePeriodFrom 은 지급분 묶음의 사용 기간 시작일로, 가장 먼저 지급한 날이다.
+func usePeriodFrom(grants []*models.GivenVacation) time.Time {
+	from := grants[0].GenerateDate
+	for _, grant := range grants[1:] {
+		if grant.GenerateDate.Before(from) {
+			from = grant.GenerateDate
+		}
+	}
+	return from
+}
+
+// headingSuffix 는 통지 제목 뒤에 붙일 일정 이름이다.
+func headingSuffix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " - " + name
+}
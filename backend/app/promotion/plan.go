@@ -0,0 +1,200 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/approval"
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"cywell.com/vacation-promotion/app/webhook"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoticeNotFound      = errors.New("촉진 통지를 찾을 수 없습니다")
+	ErrNotAwaitingResponse = errors.New("사용 계획을 제출할 수 있는 촉진 상태가 아닙니다")
+	ErrOutsideUsePeriod    = errors.New("연차휴가 사용 기간 안의 날짜만 계획할 수 있습니다")
+	ErrApproverRequired    = errors.New("사용 계획을 결재할 결재자를 지정해야 합니다")
+)
+
+type PlannedVacation struct {
+	StartDate time.Time
+	EndDate   time.Time
+	HalfFirst bool
+	HalfLast  bool
+}
+
+// UsePlanResult 는 1차 촉진에 대한 멤버의 사용 계획 제출 결과이다.
+type UsePlanResult struct {
+	Plan           models.VacationPlan
+	GivenVacations []*models.GivenVacation
+	PlannedDays    float32
+	UnplannedDays  float32
+	Histories      []models.VacationPromotionHistory
+}
+
+// SubmitUsePlan 은 1차 촉진 통지에 대한 사용 계획을 촉진 휴가로 신청한다.
+// 통지된 지급분들에서 만료가 이른 순으로 일수를 예약하고 통지를 확인 처리한 뒤,
+// 지급분마다 미사용 일수를 모두 계획했으면 FirstComplete, 아니면 SecondNeed 로 바꾼다.
+// approverIDs 가 있으면 첫 결재자에게 휴가 신청 알림을 보낸다.
+// approverIDs 가 없으면 회사 결재 설정이 결재 없는 승인을 허용할 때만 바로 승인 완료되고, 아니면 ErrApproverRequired 를 반환한다.
+func (e *Engine) SubmitUsePlan(memberID, notificationID uint, vacations []PlannedVacation, approverIDs []uint) (UsePlanResult, error) {
+	var result UsePlanResult
+	today := calendar.DateOnly(e.now())
+
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		notice, err := findNotice(tx, memberID, notificationID, enums.NotificationTypeVacationFirstPromotion)
+		if err != nil {
+			return err
+		}
+
+		grants, err := noticeGrants(tx, notice, enums.VacationPromotionStateFirstNoti)
+		if err != nil {
+			return err
+		}
+		if len(grants) == 0 {
+			return ErrNotAwaitingResponse
+		}
+		result.GivenVacations = grants
+		member := grants[0].Member
+		if len(approverIDs) == 0 {
+			setting, err := approval.LoadSetting(tx, member.CompanyID)
+			if err != nil {
+				return err
+			}
+			if !setting.AutoApprovePromotionPlans {
+				return ErrApproverRequired
+			}
+		}
+		for _, vacation := range vacations {
+			if vacation.EndDate.Before(vacation.StartDate) || calendar.DateOnly(vacation.StartDate).Before(today) || !calendar.DateOnly(vacation.EndDate).Before(calendar.DateOnly(grants[0].ExpireDate)) {
+				return ErrOutsideUsePeriod
+			}
+		}
+
+		result.Plan = models.VacationPlan{
+			MemberID:       memberID,
+			ApplyDate:      e.now(),
+			CompleteState:  len(approverIDs) == 0,
+			NotificationID: &notice.ID,
+		}
+		if err := tx.Create(&result.Plan).Error; err != nil {
+			return err
+		}
+		for i, approverID := range approverIDs {
+			approverOrder := models.ApproverOrder{
+				VacationPlanID: result.Plan.ID,
+				Order:          i + 1,
+				MemberID:       approverID,
+			}
+			if err := tx.Create(&approverOrder).Error; err != nil {
+				return err
+			}
+		}
+		if len(approverIDs) > 0 {
+			if err := approval.NotifyApprover(tx, result.Plan.ID, approverIDs[0], member.Name); err != nil {
+				return err
+			}
+		}
+
+		before := unusedDays(grants)
+		for _, vacation := range vacations {
+			applyVacation := models.ApplyVacation{
+				VacationPlanID: result.Plan.ID,
+				MemberID:       memberID,
+				StartDate:      vacation.StartDate,
+				EndDate:        vacation.EndDate,
+				HalfFirst:      vacation.HalfFirst,
+				HalfLast:       vacation.HalfLast,
+				VacationTypeID: enums.VacationTypePromotion,
+			}
+			if err := tx.Create(&applyVacation).Error; err != nil {
+				return err
+			}
+			if err := ledger.ReserveFromGrants(tx, applyVacation, grants, "촉진 사용 계획"); err != nil {
+				return err
+			}
+			if result.Plan.CompleteState {
+				if err := ledger.Consume(tx, applyVacation, "촉진 사용 계획"); err != nil {
+					return err
+				}
+			}
+			result.Plan.ApplyVacations = append(result.Plan.ApplyVacations, applyVacation)
+		}
+		if err := webhook.EmitMember(tx, memberID, webhook.EventPlanCreated, webhook.PlanDataOf(result.Plan)); err != nil {
+			return err
+		}
+		result.UnplannedDays = unusedDays(grants)
+		result.PlannedDays = before - result.UnplannedDays
+
+		// 통지 확인 처리와 회사 관리자에게 제출 알림
+		if err := acknowledge(tx, memberID, notice.ID, e.now()); err != nil {
+			return err
+		}
+		if err := notifyAdmins(tx, grants[0], notify.Message{
+			TypeID:         enums.NotificationTypeVacationFirstPromotionAccept,
+			Contents:       fmt.Sprintf("%s님이 1차 촉진에 대해 %.1f일 사용 계획을 제출했습니다. (미계획 %.1f일)", member.Name, result.PlannedDays, result.UnplannedDays),
+			VacationPlanID: &result.Plan.ID,
+		}); err != nil {
+			return err
+		}
+
+		for _, grant := range grants {
+			var next uint = enums.VacationPromotionStateFirstComplete
+			reason := fmt.Sprintf("사용 계획 %d 제출 (%.1f일, 미사용 일수 전부 계획)", result.Plan.ID, result.PlannedDays)
+			if grant.RemainingDays > 0 {
+				next = enums.VacationPromotionStateSecondNeed
+				reason = fmt.Sprintf("사용 계획 %d 제출 (%.1f일, 미계획 %.1f일)", result.Plan.ID, result.PlannedDays, grant.RemainingDays)
+			}
+			history, err := transition(tx, grant, next, reason, &memberID, &notice.ID)
+			if err != nil {
+				return err
+			}
+			result.Histories = append(result.Histories, *history)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// findNotice 는 멤버가 받은 notificationTypeID 종류의 촉진 알림을 찾는다.
+func findNotice(tx *gorm.DB, memberID, notificationID, notificationTypeID uint) (models.Notification, error) {
+	recipient, err := notify.Get(tx, memberID, notificationID)
+	if err != nil {
+		if errors.Is(err, notify.ErrNotFound) {
+			return models.Notification{}, ErrNoticeNotFound
+		}
+		return models.Notification{}, err
+	}
+	notice := recipient.Notification
+	if notice.NotificationTypeID != notificationTypeID || notice.GivenVacationID == nil {
+		return models.Notification{}, ErrNoticeNotFound
+	}
+	return notice, nil
+}
+
+// acknowledge 는 멤버가 알림을 확인했다고 표시한다. 읽지 않은 알림이면 읽은 시각도 함께 남긴다.
+func acknowledge(tx *gorm.DB, memberID, notificationID uint, now time.Time) error {
+	_, err := notify.Acknowledge(tx, memberID, notificationID, now)
+	return err
+}
+
+// notifyAdmins 는 지급분 멤버가 속한 회사의 관리자들에게 알림을 보낸다.
+// message 에 지급분이 없으면 grant 를 대상으로 한다.
+func notifyAdmins(tx *gorm.DB, grant *models.GivenVacation, message notify.Message) error {
+	adminIDs, err := notify.CompanyAdmins(tx, grant.Member.CompanyID)
+	if err != nil || len(adminIDs) == 0 {
+		return err
+	}
+	if message.GivenVacationID == nil {
+		grantID := grant.ID
+		message.GivenVacationID = &grantID
+	}
+	_, err = notify.Send(tx, message, adminIDs)
+	return err
+}
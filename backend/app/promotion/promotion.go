@@ -0,0 +1,283 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 연차 사용 촉진 (근로기준법 제61조)
+// 1차: 사용 기간 만료 6개월 전을 기준으로 10일 이내에 미사용 일수를 알리고 사용 시기를 정해 통보하도록 촉구
+// 근로자는 촉구를 받은 날부터 10일 이내에 사용 시기를 정해 통보
+// 2차: 근로자가 통보하지 않으면 만료 2개월 전까지 사용자가 사용 시기를 정해 서면 통보
+//
+// 1년 미만 근로자의 월 단위 연차 (제61조 제2항)
+// 최초 9일: 만료 3개월 전부터 10일 이내 1차, 제출 10일, 만료 1개월 전까지 2차
+// 마지막 2일: 만료 1개월 전부터 5일 이내 1차, 제출 5일, 만료 10일 전까지 2차
+
+// 지급분의 촉진 일정 종류
+const (
+	TrackAnnual        = "annual"          // 1년 이상 연차
+	TrackFirstYearNine = "first_year_nine" // 1년 미만 최초 9일
+	TrackFirstYearTwo  = "first_year_two"  // 1년 미만 마지막 2일
+)
+
+// schedule 은 만료일 기준 촉진 기한이다.
+type schedule struct {
+	firstNoticeMonths  int // 1차 촉진 시작 (만료 n개월 전)
+	noticeWindowDays   int // 1차 촉진 기간
+	responseDays       int // 사용 시기 제출 기간
+	secondNoticeMonths int // 2차 촉진 기한 (만료 n개월 전)
+	secondNoticeDays   int // 2차 촉진 기한 (만료 n일 전). secondNoticeMonths 가 0 일 때 사용
+}
+
+var schedules = map[string]schedule{
+	TrackAnnual:        {firstNoticeMonths: 6, noticeWindowDays: 10, responseDays: 10, secondNoticeMonths: 2},
+	TrackFirstYearNine: {firstNoticeMonths: 3, noticeWindowDays: 10, responseDays: 10, secondNoticeMonths: 1},
+	TrackFirstYearTwo:  {firstNoticeMonths: 1, noticeWindowDays: 5, responseDays: 5, secondNoticeDays: 10},
+}
+
+// 통지 문구에 쓰는 일정별 이름과 근거 조항
+var trackLabels = map[string]struct{ name, article string }{
+	TrackAnnual:        {"", "근로기준법 제61조 제1항"},
+	TrackFirstYearNine: {"1년 미만 근로자 최초 9일분", "근로기준법 제61조 제2항 제1호"},
+	TrackFirstYearTwo:  {"1년 미만 근로자 마지막 2일분", "근로기준법 제61조 제2항 제2호"},
+}
+
+// 1년 미만 근로자 지급분 중 앞 9개월에 발생한 지급분. 그 뒤 2개월분은 TrackFirstYearTwo
+const firstYearNineMonths = 9
+
+var ErrInvalidTransition = errors.New("허용되지 않는 촉진 상태 변경입니다")
+
+// 상태별로 이동할 수 있는 다음 상태
+var transitions = map[uint][]uint{
+	enums.VacationPromotionStateNone:          {enums.VacationPromotionStateFirstNoti},
+	enums.VacationPromotionStateFirstNoti:     {enums.VacationPromotionStateFirstComplete, enums.VacationPromotionStateSecondNeed},
+	enums.VacationPromotionStateFirstComplete: {enums.VacationPromotionStateSecondNeed},
+	enums.VacationPromotionStateSecondNeed:    {enums.VacationPromotionStateSecondNoti, enums.VacationPromotionStateSecondComplete},
+	enums.VacationPromotionStateSecondNoti:    {enums.VacationPromotionStateSecondComplete},
+}
+
+// Deadlines 는 한 지급분의 촉진 기한이다.
+type Deadlines struct {
+	Track           string     // 촉진 일정 종류. 기한은 일정마다 다르다
+	FirstNoticeFrom time.Time  // 1차 촉진 시작일 (연차는 만료 6개월 전)
+	FirstNoticeBy   time.Time  // 1차 촉진 기한 (연차는 시작일부터 10일 이내)
+	FirstNoticeDue  time.Time  // 회사 설정에 따라 앞당긴 1차 촉진 기한. 1차 촉진 시작일보다 앞서지 않는다
+	FirstNotifiedAt *time.Time // 1차 촉진을 보낸 시각
+	ResponseBy      time.Time  // 사용 시기 제출 기한 (1차 촉진 후 10일 이내)
+	SecondNoticeBy  time.Time  // 2차 촉진 기한 (만료 2개월 전)
+	SecondNoticeDue time.Time  // 회사 설정에 따라 앞당긴 2차 촉진 기한. 제출 기한보다 앞서지 않는다
+	ExpireDate      time.Time
+}
+
+// DeadlinesOf 는 지급분의 만료일과 1차 촉진 시각, 회사 촉진 설정으로 촉진 기한을 계산한다.
+// 1차 촉진 전이면 제출 기한은 회사의 1차 촉진 기한(FirstNoticeDue)에 보냈다고 가정해 계산한다.
+// 설정한 제출 기간이 법정 기간보다 짧으면 법정 기간을 쓴다.
+func DeadlinesOf(grant models.GivenVacation, firstNotifiedAt *time.Time, setting models.PromotionSetting) Deadlines {
+	track := TrackOf(grant)
+	sched := schedules[track]
+	expireDate := calendar.DateOnly(grant.ExpireDate)
+	firstNoticeFrom := expireDate.AddDate(0, -sched.firstNoticeMonths, 0)
+	deadlines := Deadlines{
+		Track:           track,
+		FirstNoticeFrom: firstNoticeFrom,
+		FirstNoticeBy:   firstNoticeFrom.AddDate(0, 0, sched.noticeWindowDays),
+		FirstNotifiedAt: firstNotifiedAt,
+		SecondNoticeBy:  expireDate.AddDate(0, -sched.secondNoticeMonths, -sched.secondNoticeDays),
+		ExpireDate:      expireDate,
+	}
+	deadlines.FirstNoticeDue = deadlines.FirstNoticeBy.AddDate(0, 0, -setting.FirstNoticeOffsetDays)
+	if deadlines.FirstNoticeDue.Before(firstNoticeFrom) {
+		deadlines.FirstNoticeDue = firstNoticeFrom
+	}
+
+	responseDays := sched.responseDays
+	if setting.ResponseDays > responseDays {
+		responseDays = setting.ResponseDays
+	}
+	deadlines.ResponseBy = deadlines.FirstNoticeDue.AddDate(0, 0, responseDays)
+	if firstNotifiedAt != nil {
+		deadlines.ResponseBy = calendar.DateOnly(*firstNotifiedAt).AddDate(0, 0, responseDays)
+	}
+
+	deadlines.SecondNoticeDue = deadlines.SecondNoticeBy.AddDate(0, 0, -setting.SecondNoticeOffsetDays)
+	if deadlines.SecondNoticeDue.Before(deadlines.ResponseBy) {
+		deadlines.SecondNoticeDue = deadlines.ResponseBy
+	}
+	if deadlines.SecondNoticeDue.After(deadlines.SecondNoticeBy) {
+		deadlines.SecondNoticeDue = deadlines.SecondNoticeBy
+	}
+	return deadlines
+}
+
+// TrackOf 는 지급분의 촉진 일정 종류이다.
+// 1년 미만 월 단위 지급분(선지급 포함)은 발생일이 입사 9개월 이내이면 최초 9일, 그 뒤면 마지막 2일 일정을 따른다.
+// 1년 미만 지급분은 모두 입사 1주년에 소멸하므로 만료일로 입사 9개월 시점을 계산한다.
+func TrackOf(grant models.GivenVacation) string {
+	if grant.GenerateRule != accrual.RuleMonthly && grant.GenerateRule != accrual.RuleHirePreGiven {
+		return TrackAnnual
+	}
+	nineMonths := calendar.DateOnly(grant.ExpireDate).AddDate(0, firstYearNineMonths-12, 0)
+	if calendar.DateOnly(grant.GenerateDate).After(nineMonths) {
+		return TrackFirstYearTwo
+	}
+	return TrackFirstYearNine
+}
+
+// Applicable 은 촉진 대상 지급분인지 확인한다. 이월분과 퇴직 정산분은 촉진하지 않는다.
+func Applicable(grant models.GivenVacation) bool {
+	return grant.GenerateRule != accrual.RuleCarryOver && grant.GenerateRule != accrual.RuleRetirementSettlement
+}
+
+func CanTransition(from, to uint) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition 은 지급분의 촉진 상태를 바꾸고 이력을 남긴다. actorID 가 nil 이면 스케줄러에 의한 변경이다.
+func Transition(tx *gorm.DB, grant *models.GivenVacation, to uint, reason string, actorID *uint) (*models.VacationPromotionHistory, error) {
+	return transition(tx, grant, to, reason, actorID, nil)
+}
+
+// transition 은 촉진 알림에 따른 상태 변경이면 이력에 알림을 함께 남긴다.
+func transition(tx *gorm.DB, grant *models.GivenVacation, to uint, reason string, actorID, notificationID *uint) (*models.VacationPromotionHistory, error) {
+	from := grant.VacationPromotionStateID
+	if !CanTransition(from, to) {
+		return nil, fmt.Errorf("%w (%d → %d)", ErrInvalidTransition, from, to)
+	}
+
+	result := tx.Model(&models.GivenVacation{}).
+		Where("id = ? AND vacation_promotion_state_id = ?", grant.ID, from).
+		Update("vacation_promotion_state_id", to)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w (다른 요청이 먼저 상태를 변경함)", ErrInvalidTransition)
+	}
+	grant.VacationPromotionStateID = to
+
+	history := models.VacationPromotionHistory{
+		GivenVacationID: grant.ID,
+		MemberID:        grant.MemberID,
+		FromStateID:     from,
+		ToStateID:       to,
+		ActorID:         actorID,
+		Reason:          reason,
+		NotificationID:  notificationID,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, err
+	}
+	if err := webhook.EmitMember(tx, grant.MemberID, webhook.EventPromotionStateChanged, webhook.PromotionStateDataOf(history)); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Record 는 상태를 바꾸지 않는 촉진 절차(노무수령 거부 통지 등)를 현재 상태 그대로 이력에 남긴다.
+func Record(tx *gorm.DB, grant *models.GivenVacation, reason string, actorID, notificationID *uint) (*models.VacationPromotionHistory, error) {
+	history := models.VacationPromotionHistory{
+		GivenVacationID: grant.ID,
+		MemberID:        grant.MemberID,
+		FromStateID:     grant.VacationPromotionStateID,
+		ToStateID:       grant.VacationPromotionStateID,
+		ActorID:         actorID,
+		Reason:          reason,
+		NotificationID:  notificationID,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// groupGrants 는 같은 멤버, 촉진 일정, 만료일의 지급분을 한 통지로 묶는다. 묶음과 묶음 안의 순서는 grants 순서를 따른다.
+// 1년 미만 근로자의 월 단위 지급분은 최초 9일분과 마지막 2일분이 각각 한 통지가 된다.
+func groupGrants(grants []*models.GivenVacation) [][]*models.GivenVacation {
+	index := make(map[string]int)
+	groups := make([][]*models.GivenVacation, 0)
+	for _, grant := range grants {
+		key := fmt.Sprintf("%d/%s/%s", grant.MemberID, TrackOf(*grant), dateKey(calendar.DateOnly(grant.ExpireDate)))
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], grant)
+	}
+	return groups
+}
+
+// noticeGrants 는 촉진 알림으로 state 상태가 되었고 지금도 그 상태인 지급분을 잠그고 가져온다.
+func noticeGrants(tx *gorm.DB, notice models.Notification, state uint) ([]*models.GivenVacation, error) {
+	var grantIDs []uint
+	if err := tx.Model(&models.VacationPromotionHistory{}).
+		Where("notification_id = ? AND to_state_id = ? AND from_state_id <> to_state_id", notice.ID, state).
+		Pluck("given_vacation_id", &grantIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(grantIDs) == 0 && notice.GivenVacationID != nil {
+		grantIDs = []uint{*notice.GivenVacationID}
+	}
+
+	var grants []models.GivenVacation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Member").
+		Where("id IN ? AND vacation_promotion_state_id = ?", grantIDs, state).
+		Order("expire_date ASC, generate_date ASC, id ASC").
+		Find(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	pointers := make([]*models.GivenVacation, 0, len(grants))
+	for i := range grants {
+		pointers = append(pointers, &grants[i])
+	}
+	return pointers, nil
+}
+
+// unusedDays 는 지급분들의 남은 일수 합계이다.
+func unusedDays(grants []*models.GivenVacation) float32 {
+	var days float32
+	for _, grant := range grants {
+		days += grant.RemainingDays
+	}
+	return days
+}
+
+// FirstNotifiedAt 은 지급분이 마지막으로 1차 촉진 상태가 된 시각이다.
+func FirstNotifiedAt(tx *gorm.DB, givenVacationID uint) (*time.Time, error) {
+	var histories []models.VacationPromotionHistory
+	if err := tx.Where("given_vacation_id = ? AND to_state_id = ? AND from_state_id <> to_state_id", givenVacationID, enums.VacationPromotionStateFirstNoti).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return nil, nil
+	}
+	return &histories[0].CreatedAt, nil
+}
+
+// Histories 는 지급분의 촉진 이력을 시간순으로 반환한다.
+func Histories(tx *gorm.DB, givenVacationID uint) ([]models.VacationPromotionHistory, error) {
+	var histories []models.VacationPromotionHistory
+	err := tx.Where("given_vacation_id = ?", givenVacationID).Order("created_at ASC, id ASC").Find(&histories).Error
+	return histories, err
+}
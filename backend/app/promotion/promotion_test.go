@@ -0,0 +1,127 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDeadlinesOf(t *testing.T) {
+	annual := models.GivenVacation{GenerateRule: accrual.RuleAnniversary, GenerateDate: date(2025, 1, 1), ExpireDate: date(2026, 1, 1)}
+	notifiedAt := time.Date(2025, 7, 5, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		grant      models.GivenVacation
+		notifiedAt *time.Time
+		setting    models.PromotionSetting
+		want       Deadlines
+	}{
+		{
+			name:  "연차 법정 기한",
+			grant: annual,
+			want: Deadlines{
+				Track:           TrackAnnual,
+				FirstNoticeFrom: date(2025, 7, 1),
+				FirstNoticeBy:   date(2025, 7, 11),
+				FirstNoticeDue:  date(2025, 7, 11),
+				ResponseBy:      date(2025, 7, 21),
+				SecondNoticeBy:  date(2025, 11, 1),
+				SecondNoticeDue: date(2025, 11, 1),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+		{
+			name:       "1차 촉진 후 제출 기한은 통지일 기준",
+			grant:      annual,
+			notifiedAt: &notifiedAt,
+			want: Deadlines{
+				Track:           TrackAnnual,
+				FirstNoticeFrom: date(2025, 7, 1),
+				FirstNoticeBy:   date(2025, 7, 11),
+				FirstNoticeDue:  date(2025, 7, 11),
+				FirstNotifiedAt: &notifiedAt,
+				ResponseBy:      date(2025, 7, 15),
+				SecondNoticeBy:  date(2025, 11, 1),
+				SecondNoticeDue: date(2025, 11, 1),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+		{
+			name:    "앞당긴 기한은 시작일과 제출 기한보다 앞서지 않음",
+			grant:   annual,
+			setting: models.PromotionSetting{FirstNoticeOffsetDays: 20, SecondNoticeOffsetDays: 200, ResponseDays: 15},
+			want: Deadlines{
+				Track:           TrackAnnual,
+				FirstNoticeFrom: date(2025, 7, 1),
+				FirstNoticeBy:   date(2025, 7, 11),
+				FirstNoticeDue:  date(2025, 7, 1),
+				ResponseBy:      date(2025, 7, 16),
+				SecondNoticeBy:  date(2025, 11, 1),
+				SecondNoticeDue: date(2025, 7, 16),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+		{
+			name:    "법정 기간보다 짧은 제출 기간은 법정 기간",
+			grant:   annual,
+			setting: models.PromotionSetting{ResponseDays: 5},
+			want: Deadlines{
+				Track:           TrackAnnual,
+				FirstNoticeFrom: date(2025, 7, 1),
+				FirstNoticeBy:   date(2025, 7, 11),
+				FirstNoticeDue:  date(2025, 7, 11),
+				ResponseBy:      date(2025, 7, 21),
+				SecondNoticeBy:  date(2025, 11, 1),
+				SecondNoticeDue: date(2025, 11, 1),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+		{
+			name:  "1년 미만 최초 9일",
+			grant: models.GivenVacation{GenerateRule: accrual.RuleMonthly, GenerateDate: date(2025, 2, 1), ExpireDate: date(2026, 1, 1)},
+			want: Deadlines{
+				Track:           TrackFirstYearNine,
+				FirstNoticeFrom: date(2025, 10, 1),
+				FirstNoticeBy:   date(2025, 10, 11),
+				FirstNoticeDue:  date(2025, 10, 11),
+				ResponseBy:      date(2025, 10, 21),
+				SecondNoticeBy:  date(2025, 12, 1),
+				SecondNoticeDue: date(2025, 12, 1),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+		{
+			name:  "1년 미만 마지막 2일",
+			grant: models.GivenVacation{GenerateRule: accrual.RuleMonthly, GenerateDate: date(2025, 11, 1), ExpireDate: date(2026, 1, 1)},
+			want: Deadlines{
+				Track:           TrackFirstYearTwo,
+				FirstNoticeFrom: date(2025, 12, 1),
+				FirstNoticeBy:   date(2025, 12, 6),
+				FirstNoticeDue:  date(2025, 12, 6),
+				ResponseBy:      date(2025, 12, 11),
+				SecondNoticeBy:  date(2025, 12, 22),
+				SecondNoticeDue: date(2025, 12, 22),
+				ExpireDate:      date(2026, 1, 1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeadlinesOf(tt.grant, tt.notifiedAt, tt.setting)
+			if got.FirstNotifiedAt != tt.want.FirstNotifiedAt {
+				t.Errorf("FirstNotifiedAt = %v, want %v", got.FirstNotifiedAt, tt.want.FirstNotifiedAt)
+			}
+			got.FirstNotifiedAt, tt.want.FirstNotifiedAt = nil, nil
+			if got != tt.want {
+				t.Errorf("DeadlinesOf() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
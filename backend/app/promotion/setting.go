@@ -0,0 +1,31 @@
+package promotion
+
+import (
+	"errors"
+
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+var ErrPromotionDisabled = errors.New("연차휴가 사용 촉진을 사용하지 않는 회사입니다")
+
+// DefaultSetting 은 설정을 저장하지 않은 회사의 촉진 설정이다. 법정 기한대로 촉진하고 2차 촉진 사용 시기를 자동 제안한다.
+func DefaultSetting(companyID uint) models.PromotionSetting {
+	return models.PromotionSetting{
+		CompanyID:        companyID,
+		Enabled:          true,
+		AutoProposeDates: true,
+	}
+}
+
+// LoadSetting 은 회사의 촉진 설정이다. 저장된 설정이 없으면 DefaultSetting 을 반환한다.
+func LoadSetting(tx *gorm.DB, companyID uint) (models.PromotionSetting, error) {
+	var settings []models.PromotionSetting
+	if err := tx.Where("company_id = ?", companyID).Limit(1).Find(&settings).Error; err != nil {
+		return models.PromotionSetting{}, err
+	}
+	if len(settings) == 0 {
+		return DefaultSetting(companyID), nil
+	}
+	return settings[0], nil
+}
@@ -0,0 +1,78 @@
+package promotion
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/calendar"
+	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// Status.DeadlineType 값
+const (
+	DeadlineFirstNotice  = "first_notice"  // 1차 촉진 기한
+	DeadlineResponse     = "response"      // 사용 시기 제출 기한
+	DeadlineSecondNotice = "second_notice" // 2차 촉진 기한
+	DeadlineExpire       = "expire"        // 사용 기간 만료
+)
+
+// Status 는 현재 상태에서 다음으로 지켜야 할 기한과 지연 일수이다.
+type Status struct {
+	Deadlines    Deadlines
+	DeadlineType string     // 다음 기한 종류. 남은 절차가 없으면 빈 값
+	Deadline     *time.Time // 다음 기한
+	DaysOverdue  int        // 기한이 지난 일수
+	NeedsAction  bool       // 회사가 1차 또는 2차 촉진을 보내야 하는 상태. 촉진을 사용하지 않는 회사는 항상 false
+}
+
+// StatusOf 는 today 기준으로 지급분의 다음 촉진 기한을 계산한다. 1차, 2차 촉진 기한은 회사 설정에 따라 앞당긴 기한이다.
+func StatusOf(grant models.GivenVacation, firstNotifiedAt *time.Time, today time.Time, setting models.PromotionSetting) Status {
+	today = calendar.DateOnly(today)
+	deadlines := DeadlinesOf(grant, firstNotifiedAt, setting)
+	status := Status{Deadlines: deadlines}
+
+	var deadline time.Time
+	switch grant.VacationPromotionStateID {
+	case enums.VacationPromotionStateNone:
+		if grant.RemainingDays <= 0 {
+			return status
+		}
+		status.DeadlineType, deadline = DeadlineFirstNotice, deadlines.FirstNoticeDue
+		status.NeedsAction = setting.Enabled && !today.Before(deadlines.FirstNoticeFrom)
+	case enums.VacationPromotionStateFirstNoti:
+		status.DeadlineType, deadline = DeadlineResponse, deadlines.ResponseBy
+	case enums.VacationPromotionStateSecondNeed:
+		status.DeadlineType, deadline = DeadlineSecondNotice, deadlines.SecondNoticeDue
+		status.NeedsAction = setting.Enabled
+	case enums.VacationPromotionStateSecondNoti:
+		status.DeadlineType, deadline = DeadlineExpire, deadlines.ExpireDate
+	default:
+		return status
+	}
+
+	status.Deadline = &deadline
+	if today.After(deadline) {
+		status.DaysOverdue = int(today.Sub(deadline).Hours() / 24)
+	}
+	return status
+}
+
+// FirstNotifiedAtByGrant 는 여러 지급분의 마지막 1차 촉진 시각을 한 번에 조회한다.
+func FirstNotifiedAtByGrant(tx *gorm.DB, givenVacationIDs []uint) (map[uint]*time.Time, error) {
+	notifiedAt := make(map[uint]*time.Time, len(givenVacationIDs))
+	if len(givenVacationIDs) == 0 {
+		return notifiedAt, nil
+	}
+
+	var histories []models.VacationPromotionHistory
+	if err := tx.Where("given_vacation_id IN ? AND to_state_id = ? AND from_state_id <> to_state_id", givenVacationIDs, enums.VacationPromotionStateFirstNoti).
+		Order("created_at ASC, id ASC").
+		Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	for i := range histories {
+		notifiedAt[histories[i].GivenVacationID] = &histories[i].CreatedAt
+	}
+	return notifiedAt, nil
+}
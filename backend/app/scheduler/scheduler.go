@@ -0,0 +1,237 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/database"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobRun.Status 값
+const (
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+const defaultLockTTL = time.Hour
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobLocked   = errors.New("job is already running")
+)
+
+// JobFunc 는 스케줄러가 실행하는 작업이다. ctx 는 스케줄러 종료 시 취소된다.
+type JobFunc func(ctx context.Context) error
+
+type Job struct {
+	Name        string
+	Spec        string // cron 표현식 (분 시 일 월 요일) 또는 @daily 같은 descriptor
+	Description string
+	LockTTL     time.Duration // 실행 중 다른 인스턴스가 같은 작업을 실행하지 못하게 잠그는 시간
+	Run         JobFunc
+	entryID     cron.EntryID
+}
+
+// JobStatus 는 작업 정의와 마지막 실행 결과이다.
+type JobStatus struct {
+	Name        string
+	Spec        string
+	Description string
+	NextRun     time.Time
+	LastRun     *models.JobRun
+}
+
+// Scheduler 는 프로세스 내에서 cron 스케줄로 작업을 실행한다.
+// 여러 인스턴스가 떠 있어도 job_locks 행으로 같은 작업이 동시에 실행되지 않게 한다.
+type Scheduler struct {
+	db         *database.Database
+	cron       *cron.Cron
+	instanceID string
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+func New(db *database.Database) *Scheduler {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		db:         db,
+		cron:       cron.New(),
+		instanceID: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		ctx:        ctx,
+		cancel:     cancel,
+		jobs:       make(map[string]*Job),
+	}
+}
+
+// Register 는 작업을 등록한다. Start 이전, 이후 모두 호출할 수 있다.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("job name and run func are required")
+	}
+	if job.LockTTL == 0 {
+		job.LockTTL = defaultLockTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s already registered", job.Name)
+	}
+
+	registered := job
+	entryID, err := s.cron.AddFunc(job.Spec, func() {
+		if _, err := s.execute(&registered, false); err != nil && !errors.Is(err, ErrJobLocked) {
+			log.Printf("job %s failed: %v", registered.Name, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", job.Name, err)
+	}
+	registered.entryID = entryID
+	s.jobs[job.Name] = &registered
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop 은 새 실행을 막고 실행 중인 작업의 ctx 를 취소한 뒤 종료를 기다린다.
+func (s *Scheduler) Stop() {
+	stopped := s.cron.Stop()
+	s.cancel()
+	<-stopped.Done()
+}
+
+// RunNow 는 스케줄과 관계없이 작업을 즉시 실행하고 끝날 때까지 기다린다.
+// 작업이 실패해도 기록된 실행 결과를 함께 반환한다.
+func (s *Scheduler) RunNow(name string) (*models.JobRun, error) {
+	s.mu.RLock()
+	job, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return s.execute(job, true)
+}
+
+// Jobs 는 등록된 작업과 마지막 실행 결과를 이름순으로 반환한다.
+func (s *Scheduler) Jobs() ([]JobStatus, error) {
+	s.mu.RLock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.RUnlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	statuses := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		lastRun, err := s.lastRun(job.Name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		statuses = append(statuses, JobStatus{
+			Name:        job.Name,
+			Spec:        job.Spec,
+			Description: job.Description,
+			NextRun:     s.cron.Entry(job.entryID).Next,
+			LastRun:     lastRun,
+		})
+	}
+	return statuses, nil
+}
+
+func (s *Scheduler) execute(job *Job, manual bool) (*models.JobRun, error) {
+	acquired, err := s.acquireLock(job)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrJobLocked
+	}
+	defer s.releaseLock(job)
+
+	run := models.JobRun{
+		JobName:    job.Name,
+		InstanceID: s.instanceID,
+		StartedAt:  time.Now(),
+		Status:     StatusRunning,
+		Manual:     manual,
+	}
+	if err := s.db.Create(&run).Error; err != nil {
+		return nil, err
+	}
+
+	runErr := s.runSafely(job)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = StatusSuccess
+	if runErr != nil {
+		run.Status = StatusFailed
+		run.Error = runErr.Error()
+	}
+	if err := s.db.Save(&run).Error; err != nil {
+		return &run, err
+	}
+	return &run, runErr
+}
+
+func (s *Scheduler) runSafely(job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(s.ctx)
+}
+
+// acquireLock 은 잠금이 없거나 만료된 경우에만 이 인스턴스 이름으로 잠근다.
+func (s *Scheduler) acquireLock(job *Job) (bool, error) {
+	lock := models.JobLock{JobName: job.Name}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	lockedUntil := now.Add(job.LockTTL)
+	result := s.db.Model(&models.JobLock{}).
+		Where("job_name = ? AND (locked_until IS NULL OR locked_until < ?)", job.Name, now).
+		Updates(map[string]interface{}{"locked_by": s.instanceID, "locked_until": lockedUntil})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (s *Scheduler) releaseLock(job *Job) {
+	if err := s.db.Model(&models.JobLock{}).
+		Where("job_name = ? AND locked_by = ?", job.Name, s.instanceID).
+		Update("locked_until", nil).Error; err != nil {
+		log.Printf("failed to release lock for job %s: %v", job.Name, err)
+	}
+}
+
+func (s *Scheduler) lastRun(name string) (*models.JobRun, error) {
+	var run models.JobRun
+	if err := s.db.Where("job_name = ?", name).Order("started_at DESC").First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,233 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 이벤트 이름
+const (
+	EventNotification = "notification" // 새 알림. data 는 dto.NotificationResponse
+	EventPlan         = "plan"         // 휴가 계획 상태 변경. data 는 dto.VacationPlanEventResponse
+	EventResync       = "resync"       // 놓친 이벤트를 이어 보낼 수 없으니 목록을 다시 조회하라는 이벤트
+)
+
+// EventPlan 의 상태 값
+const (
+	PlanApplied           = "applied"
+	PlanApproved          = "approved"
+	PlanApprovalCanceled  = "approval_canceled"
+	PlanRejected          = "rejected"
+	PlanRejectionCanceled = "rejection_canceled"
+)
+
+// HeartbeatInterval 은 연결이 끊기지 않도록 주석 줄을 보내는 간격이다.
+const HeartbeatInterval = 25 * time.Second
+
+const (
+	subscriptionBuffer = 32
+	backlogSize        = 100              // 멤버별로 이어 보내기 위해 보관하는 이벤트 수
+	backlogTTL         = 10 * time.Minute // 구독자가 없는 멤버의 이벤트를 보관하는 시간
+)
+
+// Event 는 SSE 로 보내는 이벤트 하나이다. ID 는 "허브 시작 시각-순번" 형식이다.
+type Event struct {
+	ID   string
+	Name string
+	Data []byte
+	seq  uint64
+}
+
+// WriteTo 는 이벤트를 SSE 형식으로 쓴다.
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+	fmt.Fprintf(&b, "event: %s\n", e.Name)
+	for _, line := range strings.Split(string(e.Data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Subscription 은 멤버 한 명의 스트림 연결이다. 처리가 밀려 버퍼가 차면 허브가 Events 를 닫으므로
+// 연결을 끊고 클라이언트가 Last-Event-ID 로 다시 접속하게 한다.
+type Subscription struct {
+	hub      *Hub
+	memberID uint
+	events   chan Event
+	closed   bool
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+type backlog struct {
+	events    []Event
+	evicted   uint64 // 버퍼에서 밀려난 마지막 순번
+	updatedAt time.Time
+}
+
+// Hub 는 프로세스 안에서 멤버별로 이벤트를 나눠 보내는 pub/sub 이다.
+// 멤버마다 최근 이벤트를 보관해 다시 접속한 연결이 놓친 이벤트를 이어 받을 수 있게 한다.
+// 허브가 다시 시작되면 순번이 새로 시작하므로 이전 ID 로 접속하면 resync 이벤트를 보낸다.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	pruned      uint64 // 보관 기간이 지나 지운 이벤트 중 가장 큰 순번
+	subscribers map[uint]map[*Subscription]struct{}
+	backlogs    map[uint]*backlog
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: make(map[uint]map[*Subscription]struct{}),
+		backlogs:    make(map[uint]*backlog),
+	}
+}
+
+// Publish 는 멤버들에게 이벤트를 보낸다. data 는 JSON 으로 보낸다. nil 허브면 아무것도 하지 않는다.
+func (h *Hub) Publish(name string, data interface{}, memberIDs ...uint) error {
+	if h == nil || len(memberIDs) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	seen := make(map[uint]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+
+		h.seq++
+		event := Event{ID: h.eventID(h.seq), Name: name, Data: raw, seq: h.seq}
+		h.keep(memberID, event, now)
+		for sub := range h.subscribers[memberID] {
+			select {
+			case sub.events <- event:
+			default:
+				h.remove(sub)
+			}
+		}
+	}
+	return nil
+}
+
+// Subscribe 는 멤버의 스트림을 연다. lastEventID 가 있으면 그 뒤의 이벤트를 replay 로 돌려주고,
+// 이어 보낼 수 없으면 resync 를 true 로 돌려준다.
+func (h *Hub) Subscribe(memberID uint, lastEventID string) (sub *Subscription, replay []Event, resync bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub = &Subscription{hub: h, memberID: memberID, events: make(chan Event, subscriptionBuffer)}
+	if h.subscribers[memberID] == nil {
+		h.subscribers[memberID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[memberID][sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, false
+	}
+	seq, ok := h.parseEventID(lastEventID)
+	if !ok || seq > h.seq {
+		return sub, nil, true
+	}
+	b := h.backlogs[memberID]
+	if b == nil {
+		return sub, nil, seq < h.pruned
+	}
+	if seq < b.evicted {
+		return sub, nil, true
+	}
+	for _, event := range b.events {
+		if event.seq > seq {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, false
+}
+
+// ResyncEvent 는 현재 위치를 ID 로 가진 resync 이벤트이다. 다음 접속은 이 위치부터 이어 받는다.
+func (h *Hub) ResyncEvent() Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Event{ID: h.eventID(h.seq), Name: EventResync, Data: []byte("{}"), seq: h.seq}
+}
+
+// Prune 은 구독자가 없고 보관 기간이 지난 멤버의 이벤트를 지운다.
+func (h *Hub) Prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for memberID, b := range h.backlogs {
+		if len(h.subscribers[memberID]) > 0 || now.Sub(b.updatedAt) < backlogTTL {
+			continue
+		}
+		if last := b.events[len(b.events)-1].seq; last > h.pruned {
+			h.pruned = last
+		}
+		delete(h.backlogs, memberID)
+	}
+}
+
+func (h *Hub) keep(memberID uint, event Event, now time.Time) {
+	b := h.backlogs[memberID]
+	if b == nil {
+		b = &backlog{}
+		h.backlogs[memberID] = b
+	}
+	if len(b.events) == backlogSize {
+		b.evicted = b.events[0].seq
+		b.events = append(b.events[:0], b.events[1:]...)
+	}
+	b.events = append(b.events, event)
+	b.updatedAt = now
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.events)
+	delete(h.subscribers[sub.memberID], sub)
+	if len(h.subscribers[sub.memberID]) == 0 {
+		delete(h.subscribers, sub.memberID)
+	}
+}
+
+func (h *Hub) eventID(seq uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (h *Hub) parseEventID(id string) (uint64, bool) {
+	epoch, seqStr, ok := strings.Cut(id, "-")
+	if !ok || epoch != h.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	return seq, err == nil
+}
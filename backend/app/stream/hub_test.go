@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayData 는 이어 받은 이벤트들의 data 를 이어 붙인다.
+func replayData(events []Event) string {
+	var data []string
+	for _, event := range events {
+		data = append(data, string(event.Data))
+	}
+	return strings.Join(data, ",")
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	h := NewHub()
+	start := h.ResyncEvent().ID
+	h.Publish(EventNotification, "a", 1)
+	h.Publish(EventNotification, "x", 2)
+	h.Publish(EventPlan, "b", 1, 2)
+	h.Publish(EventNotification, "c", 1)
+
+	sub, all, _ := h.Subscribe(1, start)
+	sub.Close()
+	if got := replayData(all); got != `"a","b","c"` {
+		t.Fatalf("replay from start = %s", got)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        string
+		resync      bool
+	}{
+		{"처음 접속", "", "", false},
+		{"시작 위치부터", start, `"a","b","c"`, false},
+		{"받은 이벤트 뒤부터", all[0].ID, `"b","c"`, false},
+		{"다른 멤버 이벤트는 제외", all[1].ID, `"c"`, false},
+		{"마지막 이벤트까지 받음", all[2].ID, "", false},
+		{"이전 허브의 ID", "old-1", "", true},
+		{"형식이 잘못된 ID", "garbage", "", true},
+		{"아직 없는 순번", h.eventID(100), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, replay, resync := h.Subscribe(1, tt.lastEventID)
+			defer sub.Close()
+			if got := replayData(replay); got != tt.want || resync != tt.resync {
+				t.Errorf("Subscribe(%q) = %s, %v, want %s, %v", tt.lastEventID, got, resync, tt.want, tt.resync)
+			}
+		})
+	}
+}
+
+func TestSubscribeResync(t *testing.T) {
+	t.Run("보관 한도를 넘겨 밀려남", func(t *testing.T) {
+		h := NewHub()
+		start := h.ResyncEvent().ID
+		for i := 0; i <= backlogSize; i++ {
+			h.Publish(EventNotification, i, 1)
+		}
+		sub, replay, resync := h.Subscribe(1, start)
+		defer sub.Close()
+		if !resync || len(replay) != 0 {
+			t.Errorf("Subscribe() = %d events, %v, want resync", len(replay), resync)
+		}
+	})
+
+	t.Run("보관 기간이 지나 지워짐", func(t *testing.T) {
+		h := NewHub()
+		start := h.ResyncEvent().ID
+		h.Publish(EventNotification, "a", 1)
+		h.Prune(time.Now().Add(backlogTTL + time.Minute))
+
+		sub, replay, resync := h.Subscribe(1, start)
+		defer sub.Close()
+		if !resync || len(replay) != 0 {
+			t.Errorf("Subscribe() = %d events, %v, want resync", len(replay), resync)
+		}
+
+		// 지운 뒤의 위치로 다시 접속하면 이어 받는다
+		other, replay, resync := h.Subscribe(2, h.ResyncEvent().ID)
+		defer other.Close()
+		if resync || len(replay) != 0 {
+			t.Errorf("Subscribe() after resync = %d events, %v", len(replay), resync)
+		}
+	})
+
+	t.Run("구독 중인 멤버는 지우지 않음", func(t *testing.T) {
+		h := NewHub()
+		start := h.ResyncEvent().ID
+		live, _, _ := h.Subscribe(1, "")
+		defer live.Close()
+		h.Publish(EventNotification, "a", 1)
+		h.Prune(time.Now().Add(backlogTTL + time.Minute))
+
+		if event := <-live.Events(); string(event.Data) != `"a"` {
+			t.Errorf("live event = %s", event.Data)
+		}
+		sub, replay, resync := h.Subscribe(1, start)
+		defer sub.Close()
+		if resync || replayData(replay) != `"a"` {
+			t.Errorf("Subscribe() = %s, %v", replayData(replay), resync)
+		}
+	})
+}
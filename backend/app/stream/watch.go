@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cywell.com/vacation-promotion/app/dto"
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// commitWindow 는 먼저 ID 를 받은 알림이 늦게 커밋되는 경우를 놓치지 않도록 다시 살펴보는 시간이다.
+const commitWindow = 2 * time.Minute
+
+// WatchNotifications 는 interval 마다 새로 커밋된 알림을 찾아 수신자에게 notification 이벤트로 보낸다.
+// 알림은 여러 트랜잭션과 작업에서 만들어지므로 커밋된 뒤에만 보내도록 DB 를 살펴본다.
+// ctx 가 취소되면 끝난다.
+func (h *Hub) WatchNotifications(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	var floor uint
+	if err := db.Model(&models.Notification{}).Select("COALESCE(MAX(id), 0)").Scan(&floor).Error; err != nil {
+		log.Println("notification stream: ", err)
+	}
+	published := make(map[uint]time.Time)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := h.publishNotifications(db, floor, published, now); err != nil {
+				log.Println("notification stream: ", err)
+			}
+			for id, at := range published {
+				if now.Sub(at) > commitWindow {
+					delete(published, id)
+				}
+			}
+			h.Prune(now)
+		}
+	}
+}
+
+func (h *Hub) publishNotifications(db *gorm.DB, floor uint, published map[uint]time.Time, now time.Time) error {
+	var notifications []models.Notification
+	if err := db.Preload("NotificationType").
+		Preload("NotificationMembers").
+		Where("id > ? AND created_at >= ?", floor, now.Add(-commitWindow)).
+		Order("id ASC").
+		Find(&notifications).Error; err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		if _, ok := published[notification.ID]; ok {
+			continue
+		}
+		published[notification.ID] = now
+		for _, recipient := range notification.NotificationMembers {
+			received := *recipient
+			received.Notification = notification
+			received.Notification.NotificationMembers = nil
+			if err := h.Publish(EventNotification, dto.MapNotificationToResponse(received), recipient.MemberID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInsecureURL      = errors.New("웹훅 주소는 https 로 시작하는 공개 주소여야 합니다")
+	ErrForbiddenAddress = errors.New("내부망, 루프백, 링크 로컬 주소로는 웹훅을 보낼 수 없습니다")
+)
+
+// carrierNAT 는 통신사 공유 주소 대역(100.64.0.0/10)이다. net.IP.IsPrivate 에 포함되지 않아 따로 막는다.
+var carrierNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// AllowInsecure 는 개발용으로 http 주소와 내부 주소 웹훅을 허용하는지이다.
+// WEBHOOK_ALLOW_INSECURE 환경 변수가 true 일 때만 허용하고 기본값은 허용하지 않는다.
+func AllowInsecure() bool {
+	allow, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_INSECURE"))
+	return allow
+}
+
+// CheckURL 은 등록할 웹훅 주소가 https 이고 호스트가 내부 주소가 아닌지 확인한다.
+// 호스트 이름이 가리키는 주소는 바뀔 수 있으므로 보낼 때 NewClient 가 다시 확인한다.
+// AllowInsecure 이면 http 와 내부 주소도 허용한다.
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || !allowedScheme(u.Scheme) || u.Hostname() == "" || u.User != nil {
+		return ErrInsecureURL
+	}
+	if AllowInsecure() {
+		return nil
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrForbiddenAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// NewClient 는 공개 주소로만 연결하는 웹훅 발송용 HTTP 클라이언트이다.
+// 이름을 풀어 얻은 주소로 연결하기 직전에 확인하므로 DNS 로 내부 주소를 가리켜도 막히고, 리다이렉트는 따라가지 않는다.
+// AllowInsecure 이면 내부 주소로도 연결한다.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !AllowInsecure() {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func allowedScheme(scheme string) bool {
+	return scheme == "https" || (scheme == "http" && AllowInsecure())
+}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || carrierNAT.Contains(ip))
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+)
+
+// PlanData 는 vacation_plan.* 이벤트의 data 이다.
+type PlanData struct {
+	VacationPlanID uint           `json:"vacation_plan_id"`
+	MemberID       uint           `json:"member_id"`
+	ApplyDate      time.Time      `json:"apply_date"`
+	ApproveStage   uint           `json:"approve_stage"`
+	CompleteState  bool           `json:"complete_state"`
+	RejectState    bool           `json:"reject_state"`
+	NotificationID *uint          `json:"notification_id"` // 촉진 통지에 대한 계획이면 해당 알림
+	Vacations      []VacationData `json:"vacations"`
+}
+
+type VacationData struct {
+	ApplyVacationID uint      `json:"apply_vacation_id"`
+	VacationTypeID  uint      `json:"vacation_type_id"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	HalfFirst       bool      `json:"half_first"`
+	HalfLast        bool      `json:"half_last"`
+	RejectState     bool      `json:"reject_state"`
+}
+
+// PlanDataOf 는 휴가 계획과 ApplyVacations 로 이벤트 data 를 만든다.
+func PlanDataOf(plan models.VacationPlan) PlanData {
+	data := PlanData{
+		VacationPlanID: plan.ID,
+		MemberID:       plan.MemberID,
+		ApplyDate:      plan.ApplyDate,
+		ApproveStage:   plan.ApproveStage,
+		CompleteState:  plan.CompleteState,
+		RejectState:    plan.RejectState,
+		NotificationID: plan.NotificationID,
+		Vacations:      make([]VacationData, 0, len(plan.ApplyVacations)),
+	}
+	for _, vacation := range plan.ApplyVacations {
+		data.Vacations = append(data.Vacations, VacationData{
+			ApplyVacationID: vacation.ID,
+			VacationTypeID:  vacation.VacationTypeID,
+			StartDate:       vacation.StartDate,
+			EndDate:         vacation.EndDate,
+			HalfFirst:       vacation.HalfFirst,
+			HalfLast:        vacation.HalfLast,
+			RejectState:     vacation.RejectState,
+		})
+	}
+	return data
+}
+
+// GrantData 는 given_vacation.created 이벤트의 data 이다.
+type GrantData struct {
+	GivenVacationID uint      `json:"given_vacation_id"`
+	MemberID        uint      `json:"member_id"`
+	Year            int       `json:"year"`
+	GivenDays       float32   `json:"given_days"`
+	GenerateDate    time.Time `json:"generate_date"`
+	ExpireDate      time.Time `json:"expire_date"`
+	GenerateRule    string    `json:"generate_rule"`
+	GenerateNote    string    `json:"generate_note"`
+}
+
+func GrantDataOf(grant models.GivenVacation) GrantData {
+	return GrantData{
+		GivenVacationID: grant.ID,
+		MemberID:        grant.MemberID,
+		Year:            grant.Year,
+		GivenDays:       grant.GivenDays,
+		GenerateDate:    grant.GenerateDate,
+		ExpireDate:      grant.ExpireDate,
+		GenerateRule:    grant.GenerateRule,
+		GenerateNote:    grant.GenerateNote,
+	}
+}
+
+// PromotionStateData 는 promotion.state_changed 이벤트의 data 이다.
+type PromotionStateData struct {
+	GivenVacationID uint      `json:"given_vacation_id"`
+	MemberID        uint      `json:"member_id"`
+	FromStateID     uint      `json:"from_state_id"`
+	ToStateID       uint      `json:"to_state_id"`
+	Reason          string    `json:"reason"`
+	ActorID         *uint     `json:"actor_id"`
+	NotificationID  *uint     `json:"notification_id"`
+	HistoryID       uint      `json:"history_id"`
+	ChangedAt       time.Time `json:"changed_at"`
+}
+
+func PromotionStateDataOf(history models.VacationPromotionHistory) PromotionStateData {
+	return PromotionStateData{
+		GivenVacationID: history.GivenVacationID,
+		MemberID:        history.MemberID,
+		FromStateID:     history.FromStateID,
+		ToStateID:       history.ToStateID,
+		Reason:          history.Reason,
+		ActorID:         history.ActorID,
+		NotificationID:  history.NotificationID,
+		HistoryID:       history.ID,
+		ChangedAt:       history.CreatedAt,
+	}
+}
+
+// NotificationData 는 notification.created 이벤트의 data 이다.
+type NotificationData struct {
+	NotificationID     uint      `json:"notification_id"`
+	NotificationTypeID uint      `json:"notification_type_id"`
+	MemberID           uint      `json:"member_id"` // 수신자
+	Contents           string    `json:"contents"`
+	GivenVacationID    *uint     `json:"given_vacation_id"`
+	VacationPlanID     *uint     `json:"vacation_plan_id"`
+	ApplyVacationID    *uint     `json:"apply_vacation_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func NotificationDataOf(notification models.Notification, memberID uint) NotificationData {
+	return NotificationData{
+		NotificationID:     notification.ID,
+		NotificationTypeID: notification.NotificationTypeID,
+		MemberID:           memberID,
+		Contents:           notification.Contents,
+		GivenVacationID:    notification.GivenVacationID,
+		VacationPlanID:     notification.VacationPlanID,
+		ApplyVacationID:    notification.ApplyVacationID,
+		CreatedAt:          notification.CreatedAt,
+	}
+}
@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// WebhookDelivery.Status 값
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead" // 재시도 횟수를 다 쓴 발송. 수동으로 다시 보낼 수 있다
+)
+
+// MaxAttempts 는 웹훅 한 건을 보내 보는 최대 횟수이다.
+const MaxAttempts = 10
+
+// 재시도 간격은 baseBackoff 부터 두 배씩 늘리고 maxBackoff 를 넘지 않는다.
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// 연결을 재사용할 수 있도록 응답 본문은 이만큼까지 읽고 버린다. 수신 서버의 응답 내용은 남기지 않는다.
+const maxResponseBody = 1024
+
+// Backoff 는 n 번째 실패 뒤 다음 발송까지 기다리는 시간이다.
+func Backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return wait
+}
+
+// Result 는 웹훅 발송 한 번의 결과이다.
+type Result struct {
+	Delivered int
+	Retried   int // 실패해서 다시 보내려고 대기 중인 수
+	Dead      int // 재시도 횟수를 다 써서 발송 실패 목록으로 옮긴 수
+}
+
+// Deliver 는 발송 시각이 된 대기 웹훅을 최대 limit 건 보낸다. 2xx 응답이면 전달한 것으로 본다.
+// 내부 주소는 client 가 막으므로 운영에서는 NewClient 로 만든 클라이언트를 넘긴다. https 가 아닌 주소는 보내지 않고 실패로 남긴다.
+// (개발용 WEBHOOK_ALLOW_INSECURE 설정이면 http 도 보낸다)
+// 비활성 웹훅의 대기 건은 다시 활성화할 때까지 보내지 않는다.
+// 수신 서버와 통신하는 동안 트랜잭션을 잡지 않도록 한 건씩 결과를 저장한다.
+func Deliver(ctx context.Context, db *gorm.DB, client *http.Client, now time.Time, limit int) (Result, error) {
+	var result Result
+
+	var deliveries []models.WebhookDelivery
+	if err := db.Preload("WebhookEndpoint").
+		Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.webhook_endpoint_id").
+		Where("webhook_endpoints.active = ?", true).
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", DeliveryPending, now).
+		Order("webhook_deliveries.next_attempt_at ASC, webhook_deliveries.id ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return result, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		statusCode, sendErr := post(ctx, client, delivery, now)
+		if err := recordAttempt(db, &delivery, statusCode, sendErr, now); err != nil {
+			return result, err
+		}
+		switch delivery.Status {
+		case DeliveryDelivered:
+			result.Delivered++
+		case DeliveryDead:
+			result.Dead++
+		default:
+			result.Retried++
+		}
+	}
+	return result, nil
+}
+
+func post(ctx context.Context, client *http.Client, delivery models.WebhookDelivery, now time.Time) (int, error) {
+	if u, err := url.Parse(delivery.WebhookEndpoint.URL); err != nil || !allowedScheme(u.Scheme) {
+		return 0, ErrInsecureURL
+	}
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.WebhookEndpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vacation-promotion-webhook/1.0")
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderSignature, Sign(delivery.WebhookEndpoint.Secret, now, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, sendError(err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// sendError 는 발송 기록에 남길 실패 원인이다. 연결한 주소나 내부 오류 내용이 API 로 드러나지 않도록 종류만 남긴다.
+func sendError(err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrForbiddenAddress):
+		return ErrForbiddenAddress
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return errors.New("응답 시간 초과")
+	}
+	return errors.New("연결 실패")
+}
+
+// recordAttempt 는 발송 결과를 저장한다. 실패하면 재시도 간격만큼 뒤로 미루고, 횟수를 다 쓰면 발송 실패 목록으로 옮긴다.
+func recordAttempt(db *gorm.DB, delivery *models.WebhookDelivery, statusCode int, sendErr error, now time.Time) error {
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	updates := map[string]interface{}{"attempts": delivery.Attempts, "last_status_code": statusCode}
+	switch {
+	case sendErr == nil:
+		delivery.Status = DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		updates["delivered_at"] = now
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = DeliveryDead
+		delivery.NextAttemptAt = nil
+		delivery.LastError = sendErr.Error()
+	default:
+		next := now.Add(Backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = sendErr.Error()
+	}
+	updates["status"] = delivery.Status
+	updates["next_attempt_at"] = delivery.NextAttemptAt
+	updates["last_error"] = delivery.LastError
+	return db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error
+}
+
+// Page 는 1부터 시작하는 페이지 번호와 크기이다.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// DeliveryFilter 는 발송 기록 조회 조건이다. 비어 있는 조건은 적용하지 않는다.
+type DeliveryFilter struct {
+	Status     string
+	EndpointID uint
+	EventType  string
+}
+
+// DeliveryPage 는 회사 웹훅 발송 기록 한 페이지이다.
+type DeliveryPage struct {
+	Items []models.WebhookDelivery // WebhookEndpoint 포함
+	Page  Page
+	Total int64
+}
+
+// ListDeliveries 는 회사 웹훅 발송 기록을 최근 것부터 조회한다. status=dead 로 발송 실패 목록을 본다.
+func ListDeliveries(tx *gorm.DB, companyID uint, filter DeliveryFilter, page Page) (DeliveryPage, error) {
+	if page.Number < 1 {
+		page.Number = 1
+	}
+	if page.Size < 1 || page.Size > 100 {
+		page.Size = 20
+	}
+	result := DeliveryPage{Page: page, Items: make([]models.WebhookDelivery, 0)}
+
+	query := tx.Model(&models.WebhookDelivery{}).
+		Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.webhook_endpoint_id").
+		Where("webhook_endpoints.company_id = ?", companyID)
+	if filter.Status != "" {
+		query = query.Where("webhook_deliveries.status = ?", filter.Status)
+	}
+	if filter.EndpointID != 0 {
+		query = query.Where("webhook_deliveries.webhook_endpoint_id = ?", filter.EndpointID)
+	}
+	if filter.EventType != "" {
+		query = query.Where("webhook_deliveries.event_type = ?", filter.EventType)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+	err := query.Session(&gorm.Session{}).
+		Preload("WebhookEndpoint").
+		Order("webhook_deliveries.id DESC").
+		Offset((page.Number - 1) * page.Size).
+		Limit(page.Size).
+		Find(&result.Items).Error
+	return result, err
+}
+
+// Redeliver 는 발송 실패했거나 대기 중인 웹훅을 바로 다시 보내도록 하고 재시도 횟수를 새로 센다.
+// 같은 본문과 이벤트 ID 로 보내므로 수신 측은 이벤트 ID 로 중복을 거를 수 있다.
+func Redeliver(tx *gorm.DB, companyID, deliveryID uint, now time.Time) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := tx.Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.webhook_endpoint_id").
+		Where("webhook_endpoints.company_id = ? AND webhook_deliveries.id = ?", companyID, deliveryID).
+		First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return delivery, ErrDeliveryNotFound
+		}
+		return delivery, err
+	}
+	if delivery.Status == DeliveryDelivered {
+		return delivery, ErrDeliveryDelivered
+	}
+
+	if err := tx.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+	}).Error; err != nil {
+		return delivery, err
+	}
+	err := tx.Preload("WebhookEndpoint").First(&delivery, delivery.ID).Error
+	return delivery, err
+}
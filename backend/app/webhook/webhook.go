@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cywell.com/vacation-promotion/app/models"
+	"gorm.io/gorm"
+)
+
+// 이벤트 종류
+const (
+	EventPlanCreated           = "vacation_plan.created"
+	EventPlanApproved          = "vacation_plan.approved" // 결재 단계마다 보낸다. 최종 승인이면 complete_state 가 true
+	EventPlanRejected          = "vacation_plan.rejected"
+	EventPlanCancelled         = "vacation_plan.cancelled" // 휴가 계획 삭제
+	EventGrantCreated          = "given_vacation.created"
+	EventPromotionStateChanged = "promotion.state_changed"
+	EventNotificationCreated   = "notification.created" // 웹훅 수신을 설정한 멤버의 알림
+	EventPing                  = "ping"                 // 주소 확인용. 구독과 관계없이 요청한 주소로만 보낸다
+)
+
+// Events 는 구독할 수 있는 이벤트이다.
+var Events = []string{
+	EventPlanCreated,
+	EventPlanApproved,
+	EventPlanRejected,
+	EventPlanCancelled,
+	EventGrantCreated,
+	EventPromotionStateChanged,
+	EventNotificationCreated,
+}
+
+// AllEvents 는 모든 이벤트를 구독한다는 뜻이다.
+const AllEvents = "*"
+
+// 서명 헤더. 값은 "t=<unix 초>,v1=<hex>" 이고 v1 은 "<t>.<본문>" 의 HMAC-SHA256 이다.
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderEventType = "X-Webhook-Event"
+)
+
+var (
+	ErrUnknownEvent      = errors.New("알 수 없는 웹훅 이벤트입니다")
+	ErrInvalidSignature  = errors.New("웹훅 서명이 올바르지 않습니다")
+	ErrSignatureExpired  = errors.New("웹훅 서명 시각이 허용 범위를 벗어났습니다")
+	ErrEndpointNotFound  = errors.New("웹훅을 찾을 수 없습니다")
+	ErrDeliveryNotFound  = errors.New("웹훅 발송 기록을 찾을 수 없습니다")
+	ErrDeliveryDelivered = errors.New("이미 전달한 웹훅입니다")
+)
+
+// Event 는 웹훅으로 보내는 JSON 본문이다. 같은 이벤트는 모든 주소에 같은 ID 로 보낸다.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CompanyID uint        `json:"company_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// ParseEvents 는 쉼표로 구분한 구독 이벤트를 검사해 정리한다.
+func ParseEvents(events []string) (string, error) {
+	known := make(map[string]bool, len(Events))
+	for _, event := range Events {
+		known[event] = true
+	}
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == AllEvents {
+			return AllEvents, nil
+		}
+		if !known[event] {
+			return "", fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	if len(result) == 0 {
+		return "", ErrUnknownEvent
+	}
+	return strings.Join(result, ","), nil
+}
+
+// Subscribes 는 웹훅이 이벤트를 구독하는지이다.
+func Subscribes(endpoint models.WebhookEndpoint, eventType string) bool {
+	for _, event := range strings.Split(endpoint.Events, ",") {
+		if event == AllEvents || event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Emit 은 이벤트를 구독하는 회사의 활성 웹훅마다 발송 대기 기록을 만든다.
+// 이벤트를 만든 트랜잭션 안에서 불러야 커밋된 이벤트만 보낸다.
+func Emit(tx *gorm.DB, companyID uint, eventType string, data interface{}) error {
+	var endpoints []models.WebhookEndpoint
+	if err := tx.Where("company_id = ? AND active = ?", companyID, true).Find(&endpoints).Error; err != nil {
+		return err
+	}
+	subscribed := make([]models.WebhookEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if Subscribes(endpoint, eventType) {
+			subscribed = append(subscribed, endpoint)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+	_, err := enqueueDeliveries(tx, subscribed, companyID, eventType, data)
+	return err
+}
+
+// EmitMember 는 멤버가 속한 회사로 이벤트를 보낸다.
+func EmitMember(tx *gorm.DB, memberID uint, eventType string, data interface{}) error {
+	var companyIDs []uint
+	if err := tx.Model(&models.Member{}).Where("id = ?", memberID).Pluck("company_id", &companyIDs).Error; err != nil {
+		return err
+	}
+	if len(companyIDs) == 0 {
+		return nil
+	}
+	return Emit(tx, companyIDs[0], eventType, data)
+}
+
+// Ping 은 웹훅 주소 하나로 확인용 이벤트를 보낸다.
+func Ping(tx *gorm.DB, endpoint models.WebhookEndpoint) (models.WebhookDelivery, error) {
+	deliveries, err := enqueueDeliveries(tx, []models.WebhookEndpoint{endpoint}, endpoint.CompanyID, EventPing, map[string]uint{"webhook_id": endpoint.ID})
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	return deliveries[0], nil
+}
+
+func enqueueDeliveries(tx *gorm.DB, endpoints []models.WebhookEndpoint, companyID uint, eventType string, data interface{}) ([]models.WebhookDelivery, error) {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	payload, err := json.Marshal(Event{ID: "evt_" + eventID, Type: eventType, CompanyID: companyID, CreatedAt: now, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookEndpointID: endpoint.ID,
+			EventID:           "evt_" + eventID,
+			EventType:         eventType,
+			Payload:           string(payload),
+			Status:            DeliveryPending,
+			NextAttemptAt:     &now,
+		})
+	}
+	err = tx.Create(&deliveries).Error
+	return deliveries, err
+}
+
+// NewSecret 은 웹훅 서명 키를 만든다.
+func NewSecret() (string, error) {
+	secret, err := randomHex(32)
+	return "whsec_" + secret, err
+}
+
+// Sign 은 서명 헤더 값을 만든다.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+// Verify 는 수신한 본문의 서명 헤더를 검사한다. 수신 측 구현과 테스트에 쓴다.
+// tolerance 가 0 보다 크면 서명 시각이 now 에서 그 이상 벗어난 요청을 거부한다.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(v1), []byte(signature(secret, t, body))) {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		if diff := now.Sub(time.Unix(unix, 0)); diff > tolerance || diff < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return nil
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cywell.com/vacation-promotion/app/accrual"
+	"cywell.com/vacation-promotion/app/approval"
+	"cywell.com/vacation-promotion/app/expiry"
+	"cywell.com/vacation-promotion/app/mailer"
+	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/notify"
+	"cywell.com/vacation-promotion/app/promotion"
+	"cywell.com/vacation-promotion/app/scheduler"
+	"cywell.com/vacation-promotion/app/webhook"
+	"cywell.com/vacation-promotion/database"
+)
+
+func registerJobs(jobScheduler *scheduler.Scheduler, db *database.Database) error {
+	jobs := []scheduler.Job{
+		{
+			Name:        "vacation-expiry",
+			Spec:        "30 0 * * *",
+			Description: "소멸일이 지난 휴가 지급분 소멸, 이월 처리",
+			Run:         expireAllCompanies(db),
+		},
+		{
+			Name:        "vacation-accrual",
+			Spec:        "0 1 * * *",
+			Description: "전체 회사 휴가 발생분 생성",
+			Run:         accrueAllCompanies(db),
+		},
+		{
+			Name:        "vacation-promotion",
+			Spec:        "0 2 * * *",
+			Description: "휴가 촉진 기한에 따른 촉진 상태 진행 (촉진을 사용하지 않는 회사는 건너뜀)",
+			Run:         advanceAllPromotions(db),
+		},
+		{
+			Name:        "approval-reminder",
+			Spec:        "0 9 * * *",
+			Description: "결재자별 결재 대기 요약 발송, 오래 대기 중인 결재 독촉과 회사 관리자 보고",
+			Run:         remindAllApprovals(db),
+		},
+		{
+			Name:        "webhook-delivery",
+			Spec:        "* * * * *",
+			Description: "대기 중인 웹훅 발송, 실패한 웹훅 재시도",
+			LockTTL:     10 * time.Minute,
+			Run:         deliverWebhooks(db),
+		},
+	}
+
+	mailConfig, err := mailer.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	if mailConfig.Enabled() {
+		sender, err := mailer.NewSMTP(mailConfig)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, scheduler.Job{
+			Name:        "notification-email",
+			Spec:        "* * * * *",
+			Description: "대기 중인 알림 이메일과 묶음 메일 발송, 실패한 메일 재시도",
+			LockTTL:     10 * time.Minute,
+			Run:         deliverNotificationEmails(db, sender),
+		})
+	} else {
+		log.Println("SMTP_HOST 가 없어 알림 이메일을 보내지 않습니다")
+	}
+
+	for _, job := range jobs {
+		if err := jobScheduler.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 한 번에 보내는 메일 수. 남은 메일은 다음 실행에서 보낸다
+const emailBatchSize = 200
+
+func deliverNotificationEmails(db *database.Database, sender mailer.Sender) scheduler.JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		result, err := notify.DeliverEmails(ctx, db.DB, sender, now, emailBatchSize)
+		if result.Retried > 0 || result.Failed > 0 {
+			log.Printf("notification-email: sent %d, retry %d, failed %d", result.Sent, result.Retried, result.Failed)
+		}
+		if err != nil {
+			return err
+		}
+
+		digests, err := notify.DeliverDigests(ctx, db.DB, sender, now, emailBatchSize)
+		if digests.Retried > 0 || digests.Failed > 0 {
+			log.Printf("notification-email digest: sent %d, retry %d, failed %d", digests.Sent, digests.Retried, digests.Failed)
+		}
+		return err
+	}
+}
+
+// 한 번에 보내는 웹훅 수와 수신 서버 응답 대기 시간
+const (
+	webhookBatchSize = 200
+	webhookTimeout   = 10 * time.Second
+)
+
+func deliverWebhooks(db *database.Database) scheduler.JobFunc {
+	if webhook.AllowInsecure() {
+		log.Println("webhook-delivery: WEBHOOK_ALLOW_INSECURE is set, http and internal addresses are allowed")
+	}
+	client := webhook.NewClient(webhookTimeout)
+	return func(ctx context.Context) error {
+		result, err := webhook.Deliver(ctx, db.DB, client, time.Now(), webhookBatchSize)
+		if result.Retried > 0 || result.Dead > 0 {
+			log.Printf("webhook-delivery: delivered %d, retry %d, dead %d", result.Delivered, result.Retried, result.Dead)
+		}
+		return err
+	}
+}
+
+func remindAllApprovals(db *database.Database) scheduler.JobFunc {
+	engine := approval.NewEngine(db, time.Now)
+	return forEachCompany(db, "approval-reminder", func(companyID uint) error {
+		if _, err := engine.SendDigests(companyID); err != nil {
+			return err
+		}
+		_, err := engine.Remind(companyID)
+		return err
+	})
+}
+
+func accrueAllCompanies(db *database.Database) scheduler.JobFunc {
+	engine := accrual.NewEngine(db, time.Now)
+	return forEachCompany(db, "vacation-accrual", func(companyID uint) error {
+		_, err := engine.AccrueCompany(companyID)
+		return err
+	})
+}
+
+func expireAllCompanies(db *database.Database) scheduler.JobFunc {
+	engine := expiry.NewEngine(db, time.Now)
+	return forEachCompany(db, "vacation-expiry", func(companyID uint) error {
+		_, err := engine.ExpireCompany(companyID)
+		return err
+	})
+}
+
+func advanceAllPromotions(db *database.Database) scheduler.JobFunc {
+	engine := promotion.NewEngine(db, time.Now)
+	return forEachCompany(db, "vacation-promotion", func(companyID uint) error {
+		_, err := engine.AdvanceCompany(companyID)
+		return err
+	})
+}
+
+// forEachCompany 는 모든 회사에 run 을 실행한다. 한 회사가 실패해도 나머지 회사는 계속 처리하고
+// 실패한 회사는 ID 와 함께 로그를 남긴 뒤 오류를 모아 반환한다.
+func forEachCompany(db *database.Database, name string, run func(companyID uint) error) scheduler.JobFunc {
+	return func(ctx context.Context) error {
+		var companyIDs []uint
+		if err := db.Model(&models.Company{}).Pluck("id", &companyIDs).Error; err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, companyID := range companyIDs {
+			if err := ctx.Err(); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			if err := run(companyID); err != nil {
+				log.Printf("%s: company %d: %v", name, companyID, err)
+				errs = append(errs, fmt.Errorf("company %d: %w", companyID, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -1,18 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"strings"
+	"time"
 
 	"cywell.com/vacation-promotion/app/enums"
+	"cywell.com/vacation-promotion/app/ledger"
 	"cywell.com/vacation-promotion/app/models"
+	"cywell.com/vacation-promotion/app/scheduler"
+	"cywell.com/vacation-promotion/app/stream"
 	"cywell.com/vacation-promotion/app/utils"
 	"cywell.com/vacation-promotion/database"
 	"cywell.com/vacation-promotion/routes"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -59,6 +65,23 @@ func main() {
 		&models.Notification{},
 		&models.ApproverOrder{},
 		&models.Organize{},
+		&models.VacationLedger{},
+		&models.JobRun{},
+		&models.JobLock{},
+		&models.PublicHoliday{},
+		&models.CompanyHoliday{},
+		&models.VacationExpiry{},
+		&models.VacationPromotionHistory{},
+		&models.VacationDenyWork{},
+		&models.PromotionEvidence{},
+		&models.PromotionSetting{},
+		&models.NotificationDelivery{},
+		&models.NotificationPreference{},
+		&models.NotificationSetting{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.ApprovalSetting{},
+		&models.ApprovalReminder{},
 	)
 
 	if err != nil {
@@ -66,8 +89,33 @@ func main() {
 	}
 	fmt.Println("Database migrated successfully")
 
+	// 원장 도입 전 지급분은 요약 컬럼이 원장 합계로 덮어써지기 전에 원장 기록을 채운다
+	err = db.Transaction(func(tx *gorm.DB) error {
+		backfilled, err := ledger.Backfill(tx)
+		if backfilled > 0 {
+			fmt.Printf("Vacation ledger backfilled: %d grants\n", backfilled)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal("failed to backfill vacation ledger: ", err)
+	}
+
+	jobScheduler := scheduler.New(db)
+	if err := registerJobs(jobScheduler, db); err != nil {
+		log.Fatal("failed to register jobs: ", err)
+	}
+	jobScheduler.Start()
+	defer jobScheduler.Stop()
+
+	// 새 알림을 SSE 구독자에게 보낸다
+	hub := stream.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.WatchNotifications(ctx, db.DB, 2*time.Second)
+
 	api := app.Group("/api")
-	routes.RegisterAPI(api, db)
+	routes.RegisterAPI(api, db, jobScheduler, hub)
 
 	app.Static("/", "../dist/front_web/browser/")
 
@@ -87,6 +135,7 @@ func MigrateAndSeed(db *database.Database) error {
 		&models.VacationType{},
 		&models.VacationPromotionState{},
 		&models.VacationGenerateType{},
+		&models.VacationLedgerType{},
 		&models.NotificationType{},
 		&models.AdminType{},
 	)
@@ -143,6 +192,20 @@ func MigrateAndSeed(db *database.Database) error {
 		db.FirstOrCreate(&vps, models.VacationPromotionState{ID: vps.ID})
 	}
 
+	//휴가 원장 타입
+	vacationLedgerTypes := []models.VacationLedgerType{
+		{ID: enums.VacationLedgerTypeGrant, TypeName: "지급"},
+		{ID: enums.VacationLedgerTypeReserve, TypeName: "신청 예약"},
+		{ID: enums.VacationLedgerTypeConsume, TypeName: "사용"},
+		{ID: enums.VacationLedgerTypeRelease, TypeName: "예약 해제"},
+		{ID: enums.VacationLedgerTypeAdjust, TypeName: "조정"},
+		{ID: enums.VacationLedgerTypeExpire, TypeName: "소멸"},
+		{ID: enums.VacationLedgerTypeCarryOver, TypeName: "이월"},
+	}
+	for _, vlt := range vacationLedgerTypes {
+		db.FirstOrCreate(&vlt, models.VacationLedgerType{ID: vlt.ID})
+	}
+
 	//알림 타입
 	notificationTypes := []models.NotificationType{
 		{ID: enums.NotificationTypeNormal, TypeName: "일반"},
@@ -153,6 +216,8 @@ func MigrateAndSeed(db *database.Database) error {
 		{ID: enums.NotificationTypeVacationSecondPromotionAccept, TypeName: "2차 촉진 확인"},
 		{ID: enums.NotificationTypeVacationDenyWork, TypeName: "노무 거부"},
 		{ID: enums.NotificationTypeVacationDenyWorkAccept, TypeName: "노무 거부 확인"},
+		{ID: enums.NotificationTypeApprovalDigest, TypeName: "결재 대기 요약"},
+		{ID: enums.NotificationTypeApprovalReminder, TypeName: "결재 독촉"},
 	}
 	for _, nt := range notificationTypes {
 		db.FirstOrCreate(&nt, models.NotificationType{ID: nt.ID})
@@ -3,19 +3,23 @@ package routes
 import (
 	"cywell.com/vacation-promotion/app/auth"
 	"cywell.com/vacation-promotion/app/controllers/api"
+	"cywell.com/vacation-promotion/app/scheduler"
+	"cywell.com/vacation-promotion/app/stream"
 	"cywell.com/vacation-promotion/database"
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterAPI(apiRouter fiber.Router, db *database.Database) {
+func RegisterAPI(apiRouter fiber.Router, db *database.Database, jobScheduler *scheduler.Scheduler, hub *stream.Hub) {
 	apiRouter.Post("/update", api.UpdateHandler())
 	apiRouter.Get("/have-update", api.HaveUpdateHandler())
 	registerAuth(apiRouter, db)
 	registerCompanies(apiRouter, db)
 	registerGroups(apiRouter, db)
-	registerMembers(apiRouter, db)
-	registerVacations(apiRouter, db)
+	registerMembers(apiRouter, db, hub)
+	registerVacations(apiRouter, db, hub)
 	registerOrganizes(apiRouter, db)
+	registerHolidays(apiRouter, db)
+	registerJobs(apiRouter, db, jobScheduler)
 }
 
 func registerAuth(apiRouter fiber.Router, db *database.Database) {
@@ -33,6 +37,11 @@ func registerCompanies(apiRouter fiber.Router, db *database.Database) {
 	company.Get("/", api.GetCompanyHandler(db))
 	company.Post("/", api.UpdateCompanyHandler(db))
 	company.Delete("/", api.DeleteCompanyHandler(db))
+	company.Put("/advance-vacation", api.UpdateAdvanceVacationHandler(db)) // advance_vacation_days
+	company.Get("/promotion-setting", api.GetPromotionSettingHandler(db))
+	company.Put("/promotion-setting", api.UpdatePromotionSettingHandler(db)) // 1차, 2차 촉진 오프셋, 제출 기간, 사용 시기 자동 제안
+	company.Get("/approval-setting", api.GetApprovalSettingHandler(db))
+	company.Put("/approval-setting", api.UpdateApprovalSettingHandler(db)) // digest_enabled, reminder_days, escalation_days, auto_approve_promotion_plans
 
 	members := company.Group("/members")
 	members.Get("/", api.GetCompanyMembersHandler(db))
@@ -46,7 +55,39 @@ func registerCompanies(apiRouter fiber.Router, db *database.Database) {
 	vacations := company.Group("/vacations")
 	vacations.Get("/", api.GetVacationsByPeriodHandler(db))
 	vacations.Get("/plans", api.GetVacationPlansByPeriodHandler(db))
-	vacations.Get("/promotions", api.GetPromotionsHandler(db)) //촉진현황 가져오기
+	vacations.Get("/promotions", api.GetPromotionsHandler(db)) //촉진현황 가져오기. state, organizeID, groupID, needs_action, track
+	vacations.Post("/promotions/advance", api.AdvanceCompanyPromotionsHandler(db))
+	vacations.Get("/promotions/first-notices", api.PreviewFirstNoticesHandler(db)) // organizeID
+	vacations.Post("/promotions/first-notices", api.SendFirstNoticesHandler(db))
+	vacations.Get("/promotions/second-notices", api.PreviewSecondNoticesHandler(db)) // organizeID. 지급분별 제안 날짜
+	vacations.Post("/promotions/second-notices", api.SendSecondNoticesHandler(db))
+	vacations.Get("/promotions/evidence", api.ExportCompanyEvidenceHandler(db)) // year, memberID, format(json, html)
+	vacations.Post("/promotions/evidence/verify", api.VerifyEvidenceHandler(db))
+	vacations.Get("/deny-works", api.GetDenyWorksHandler(db))                         // memberID, pending
+	vacations.Post("/apply/:applyVacationID/deny-work", api.IssueDenyWorkHandler(db)) // 노무수령 거부 통지
+	vacations.Post("/accrue", api.AccrueCompanyVacationsHandler(db))
+	vacations.Post("/expire", api.ExpireCompanyVacationsHandler(db))
+
+	holidays := company.Group("/holidays")
+	holidays.Get("/", api.GetCompanyHolidaysHandler(db)) // year
+	holidays.Post("/", api.CreateCompanyHolidayHandler(db))
+	holidays.Post("/import", api.ImportCompanyHolidaysHandler(db)) // file (.ics, .csv)
+	holidays.Put("/:holidayID", api.UpdateCompanyHolidayHandler(db))
+	holidays.Delete("/:holidayID", api.DeleteCompanyHolidayHandler(db))
+
+	notifications := company.Group("/notifications")
+	notifications.Get("/deliveries", api.GetNotificationDeliveriesHandler(db)) // status, notificationID, memberID, page, size
+	notifications.Post("/deliveries/:deliveryID/retry", api.RetryNotificationDeliveryHandler(db))
+
+	webhooks := company.Group("/webhooks")
+	webhooks.Get("/", api.GetWebhooksHandler(db))
+	webhooks.Post("/", api.CreateWebhookHandler(db))                 // url, events, description, active
+	webhooks.Get("/deliveries", api.GetWebhookDeliveriesHandler(db)) // status, webhookID, event, page, size
+	webhooks.Post("/deliveries/:deliveryID/redeliver", api.RedeliverWebhookHandler(db))
+	webhooks.Put("/:webhookID", api.UpdateWebhookHandler(db))
+	webhooks.Delete("/:webhookID", api.DeleteWebhookHandler(db))
+	webhooks.Post("/:webhookID/rotate-secret", api.RotateWebhookSecretHandler(db))
+	webhooks.Post("/:webhookID/ping", api.PingWebhookHandler(db))
 
 	organizes := company.Group("/organizes")
 	organizes.Get("/", api.GetOrganizesHandler(db))
@@ -71,26 +112,45 @@ func registerGroups(apiRouter fiber.Router, db *database.Database) {
 	vacations.Get("/plans", api.GetVacationPlansByPeriodHandler(db))
 }
 
-func registerMembers(apiRouter fiber.Router, db *database.Database) {
+func registerMembers(apiRouter fiber.Router, db *database.Database, hub *stream.Hub) {
 
 	members := apiRouter.Group("/members", auth.AuthCheckMiddleware)
 	member := members.Group("/:memberID")
 	member.Get("/profile", api.GetMemberProfileHandler(db))
+	member.Get("/balance", api.GetMemberBalanceHandler(db)) // year
 	member.Post("/deactivate", api.DeactivateMemberHandler(db))
 	member.Delete("/", api.DeleteMemberHandler(db))
+	member.Get("/retirement-settlement", api.GetRetirementSettlementHandler(db)) // daily_wage
+	member.Post("/retirement-settlement", api.SettleRetirementHandler(db))
 
 	vacations := member.Group("/vacations")
 	vacations.Get("/", api.GetVacationsByPeriodHandler(db))
-	vacations.Post("/plans", api.CreateVacationPlanHandler(db))
+	vacations.Post("/plans", api.CreateVacationPlanHandler(db, hub))
 	vacations.Get("/plans", api.GetVacationPlansByPeriodHandler(db))
+	vacations.Post("/accrue", api.AccrueMemberVacationsHandler(db))
+	vacations.Get("/ledger", api.GetVacationLedgerHandler(db)) // given_vacation_id
+	vacations.Get("/expiries", api.GetVacationExpiriesHandler(db))
+	vacations.Post("/given/:givenVacationID/adjust", api.AdjustGivenVacationHandler(db))
+	vacations.Get("/given/:givenVacationID/promotion", api.GetGrantPromotionHandler(db))
+	vacations.Get("/promotions/evidence", api.ExportMemberEvidenceHandler(db))                  // year, format(json, html)
+	vacations.Post("/promotions/:notificationID/plan", api.SubmitPromotionPlanHandler(db, hub)) // 1차 촉진 사용 계획 제출
+	vacations.Post("/promotions/:notificationID/accept", api.AcceptSecondNoticeHandler(db))     // 2차 촉진 지정 통보 확인
+	vacations.Post("/deny-works/:notificationID/accept", api.AcceptDenyWorkHandler(db))         // 노무수령 거부 통지 확인
+
+	member.Get("/notification-settings", api.GetNotificationSettingsHandler(db))
+	member.Put("/notification-settings", api.UpdateNotificationSettingsHandler(db)) // 알림 종류별 채널(앱, 이메일, 웹훅), 묶음 발송 주기, 방해 금지 시간
 
 	notifications := member.Group("/notifications")
-	notifications.Get("/", api.GetAllNotificationsHandler(db))
-	notifications.Get("/new", api.GetNewNotificationsHandler(db))
+	notifications.Get("/", api.GetAllNotificationsHandler(db))        // page, size, unread, type
+	notifications.Get("/new", api.GetNewNotificationsHandler(db))     // 확인하지 않은 알림. page, size, type
+	notifications.Get("/stream", api.StreamNotificationsHandler(hub)) // SSE. notification, plan, resync 이벤트
+	notifications.Post("/read-all", api.ReadAllNotificationsHandler(db))
+	notifications.Get("/:notificationID", api.GetNotificationHandler(db))
+	notifications.Post("/:notificationID/read", api.ReadNotificationHandler(db))
 	notifications.Post("/:notificationID/approve", api.ApproveNotificationHandler(db))
 }
 
-func registerVacations(apiRouter fiber.Router, db *database.Database) {
+func registerVacations(apiRouter fiber.Router, db *database.Database, hub *stream.Hub) {
 
 	vacations := apiRouter.Group("/vacations", auth.AuthCheckMiddleware)
 	plans := vacations.Group("/plans")
@@ -98,10 +158,10 @@ func registerVacations(apiRouter fiber.Router, db *database.Database) {
 
 	plan := plans.Group("/:planId")
 	plan.Get("/", api.GetVacationPlanHandler(db))
-	plan.Post("/approve", api.ApproveVacationPlanHandler(db))
-	plan.Post("/cancel-approve", api.CancelApproveVacationPlanHandler(db))
-	plan.Post("/reject", api.RejectVacationPlanHandler(db))
-	plan.Post("/cancel-reject", api.CancelRejectVacationPlanHandler(db))
+	plan.Post("/approve", api.ApproveVacationPlanHandler(db, hub))
+	plan.Post("/cancel-approve", api.CancelApproveVacationPlanHandler(db, hub))
+	plan.Post("/reject", api.RejectVacationPlanHandler(db, hub))
+	plan.Post("/cancel-reject", api.CancelRejectVacationPlanHandler(db, hub))
 	plan.Patch("/", api.UpdateVacationPlanHandler(db))
 	plan.Delete("/", api.DeleteVacationPlanHandler(db))
 
@@ -123,3 +183,20 @@ func registerOrganizes(apiRouter fiber.Router, db *database.Database) {
 	members := organize.Group("/members")
 	members.Post("/", api.UpdateOrganizeMembersHandler(db)) // [id]
 }
+
+func registerHolidays(apiRouter fiber.Router, db *database.Database) {
+
+	holidays := apiRouter.Group("/holidays", auth.AuthCheckMiddleware)
+	holidays.Get("/", api.GetHolidaysHandler(db)) // year, company_id
+	holidays.Post("/", api.CreatePublicHolidayHandler(db))
+	holidays.Get("/working-days", api.GetWorkingDaysHandler(db)) // start, end, half_first, half_last, company_id
+	holidays.Delete("/:holidayID", api.DeletePublicHolidayHandler(db))
+}
+
+func registerJobs(apiRouter fiber.Router, db *database.Database, jobScheduler *scheduler.Scheduler) {
+
+	// 모든 회사에 대해 실행되는 작업이므로 관리자만 조회, 실행할 수 있다
+	jobs := apiRouter.Group("/jobs", auth.AuthCheckMiddleware, auth.AdminCheckMiddleware(db))
+	jobs.Get("/", api.GetJobsHandler(jobScheduler))
+	jobs.Post("/:jobName/run", api.RunJobHandler(jobScheduler))
+}